@@ -1,5 +1,7 @@
 package broker
 
+import "context"
+
 var (
 	NotConnected = "not-connected"
 )
@@ -22,11 +24,43 @@ type SubscribeInterface interface {
 	// Unsubscribe on teardown so the subscription and its delivery goroutine do
 	// not leak.
 	Unsubscribe(subject string) error
+	// SubscribeContext passes the messages received on the subject to the handler, until ctx is done or the returned
+	// Subscription is unsubscribed. Unlike Unsubscribe(subject), stopping it leaves the other subscriptions on the
+	// subject, including the ones of the same queue, untouched. Messages are handled one at a time.
+	SubscribeContext(ctx context.Context, subject, queue string, handler MessageHandler) (Subscription, error)
+}
+
+// MessageHandler handles a message received through SubscribeContext.
+type MessageHandler func(message *Message)
+
+// Subscription is the handle of a single subscription created by SubscribeContext.
+type Subscription interface {
+	// Unsubscribe stops the subscription. It does not wait for a running handler to return and is safe to call more than once.
+	Unsubscribe() error
+	// Done is closed once the subscription has stopped, whether through Unsubscribe, its context,
+	// Unsubscribe(subject) on the handler or CloseConnection.
+	Done() <-chan struct{}
+}
+
+// ReplyHandler handles a request received through HandleRequest and returns the reply sent back to the requester.
+// A returned error is sent back as an error reply (see ErrorReply).
+type ReplyHandler func(request *Message) (*Message, error)
+
+type RequestInterface interface {
+	// Request publishes the message on the subject and waits for the first reply, until ctx is done.
+	// If the responder failed, the error reply is returned along with the error it carries (see ReplyError).
+	Request(ctx context.Context, subject string, message *Message) (*Message, error)
+	// HandleRequest registers a handler replying to the requests published on the subject.
+	// Requests are load-balanced across the handlers registered with the same queue.
+	// The handler is removed by Unsubscribe(subject).
+	HandleRequest(subject, queue string, handler ReplyHandler) error
 }
 
 type Handler interface {
 	PublishInterface
 	SubscribeInterface
+	RequestInterface
+	MonitorInterface
 	Info() string
 	DeepCopyObject() Handler
 	DeepCopyInto(Handler)
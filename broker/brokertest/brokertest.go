@@ -0,0 +1,578 @@
+// Package brokertest provides a broker.Handler test double which records the published messages and delivers them
+// synchronously, so that tests can assert on the messaging of the code under test without waiting on a real broker.
+package brokertest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/meshery/meshkit/broker"
+)
+
+var (
+	// ErrDisconnected is returned while the handler is disconnected, see Disconnect.
+	ErrDisconnected = errors.New("brokertest: handler is disconnected")
+	// ErrNoResponders is returned by Request when no handler is registered for the subject.
+	ErrNoResponders = errors.New("brokertest: no responders available for request")
+	// ErrUnsubscribed is returned by Subscribe when the subscription is removed before a message is received.
+	ErrUnsubscribed = errors.New("brokertest: subscription was removed before a message was received")
+)
+
+// Handler is a recording broker.Handler.
+//
+// Publish records the message and delivers it to the subscribers before returning: a message is delivered to one
+// subscription of every queue of the subject, in turns. Messages sent to the channel of SubscribeWithChannel block
+// until received, so tests reading the channel from the publishing goroutine should use buffered channels.
+// Subjects support the NATS `*` and `>` wildcards.
+type Handler struct {
+	mu   sync.Mutex
+	name string
+
+	published  map[string][]*broker.Message
+	requests   map[string][]*broker.Message
+	awaited    map[string]int
+	updated    chan struct{} // closed and replaced whenever a message is recorded
+	subs       []*subscription
+	turns      map[string]int // [subject::queue] => subscriptions delivered to so far
+	responders map[string]map[string]broker.ReplyHandler
+	replies    map[string]int // [subject] => requests handled so far
+
+	publishErrs   map[string]error
+	subscribeErrs map[string]error
+	disconnected  bool
+	reconnects    uint64
+	codecs        *broker.CodecRegistry
+	watchers      broker.ConnectionWatchers
+}
+
+var _ broker.Handler = (*Handler)(nil)
+
+// NewHandler returns a connected Handler.
+// The objects of the messages are normalized with the codecs, as the channel handler does, if codecs is not nil.
+func NewHandler(codecs *broker.CodecRegistry) *Handler {
+	h := &Handler{
+		name:   fmt.Sprintf("brokertest-handler--%s", uuid.Must(uuid.NewV4()).String()),
+		codecs: codecs,
+	}
+	h.reset()
+	return h
+}
+
+func (h *Handler) reset() {
+	h.published = make(map[string][]*broker.Message)
+	h.requests = make(map[string][]*broker.Message)
+	h.awaited = make(map[string]int)
+	h.updated = make(chan struct{})
+	h.turns = make(map[string]int)
+	h.responders = make(map[string]map[string]broker.ReplyHandler)
+	h.replies = make(map[string]int)
+	h.publishErrs = make(map[string]error)
+	h.subscribeErrs = make(map[string]error)
+}
+
+// Reset removes the recorded messages, subscriptions, responders and injected failures, and reconnects the handler.
+func (h *Handler) Reset() {
+	h.mu.Lock()
+	subs := h.subs
+	h.subs = nil
+	close(h.updated)
+	h.reset()
+	h.disconnected = false
+	h.reconnects = 0
+	h.mu.Unlock()
+
+	for _, s := range subs {
+		s.stop()
+	}
+}
+
+// FailPublish makes Publish and Request on the subject fail with err, or on every subject if subject is empty.
+// A nil err removes the failure.
+func (h *Handler) FailPublish(subject string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	setFailure(h.publishErrs, subject, err)
+}
+
+// FailSubscribe makes the subscriptions to the subject fail with err, or to every subject if subject is empty.
+// A nil err removes the failure.
+func (h *Handler) FailSubscribe(subject string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	setFailure(h.subscribeErrs, subject, err)
+}
+
+func setFailure(failures map[string]error, subject string, err error) {
+	if err == nil {
+		delete(failures, subject)
+		return
+	}
+	failures[subject] = err
+}
+
+func failure(failures map[string]error, subject string) error {
+	if err, ok := failures[subject]; ok {
+		return err
+	}
+	return failures[""]
+}
+
+// Disconnect simulates the loss of the connection: IsConnected reports false and publishing or subscribing fails
+// with ErrDisconnected until Reconnect. Existing subscriptions are kept, as with a reconnecting NATS client.
+// A Disconnected event with the reason is sent to the watchers of ConnectionEvents.
+func (h *Handler) Disconnect(reason error) {
+	h.mu.Lock()
+	h.disconnected = true
+	h.mu.Unlock()
+
+	event := broker.ConnectionEvent{State: broker.Disconnected, Endpoint: h.name}
+	if reason != nil {
+		event.Reason = reason.Error()
+	}
+	h.watchers.Notify(event)
+}
+
+// Reconnect ends a simulated disconnection, sending a Reconnected event to the watchers of ConnectionEvents.
+func (h *Handler) Reconnect() {
+	h.mu.Lock()
+	h.disconnected = false
+	h.reconnects++
+	h.mu.Unlock()
+
+	h.watchers.Notify(broker.ConnectionEvent{State: broker.Reconnected, Endpoint: h.name})
+}
+
+// Published returns the messages published on the subject, in order.
+func (h *Handler) Published(subject string) []*broker.Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*broker.Message(nil), h.published[subject]...)
+}
+
+// Requests returns the requests sent on the subject through Request, in order.
+func (h *Handler) Requests(subject string) []*broker.Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*broker.Message(nil), h.requests[subject]...)
+}
+
+// AwaitMessage returns the next message published on the subject which was not returned by a previous call,
+// waiting up to timeout for it to be published.
+func (h *Handler) AwaitMessage(subject string, timeout time.Duration) (*broker.Message, error) {
+	messages, err := h.AwaitMessages(subject, 1, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return messages[0], nil
+}
+
+// AwaitMessages returns the next n messages published on the subject which were not returned by a previous call,
+// waiting up to timeout for them to be published.
+func (h *Handler) AwaitMessages(subject string, n int, timeout time.Duration) ([]*broker.Message, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		h.mu.Lock()
+		from := h.awaited[subject]
+		if available := len(h.published[subject]) - from; available >= n {
+			h.awaited[subject] = from + n
+			messages := append([]*broker.Message(nil), h.published[subject][from:from+n]...)
+			h.mu.Unlock()
+			return messages, nil
+		}
+		updated := h.updated
+		h.mu.Unlock()
+
+		select {
+		case <-updated:
+		case <-deadline.C:
+			return nil, fmt.Errorf("brokertest: timed out after %s waiting for %d message(s) on subject %s", timeout, n, subject)
+		}
+	}
+}
+
+// Publish records the message and delivers it to the subscriptions of the subject before returning.
+func (h *Handler) Publish(subject string, message *broker.Message) error {
+	message, err := h.codecs.Normalize(message)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	if h.disconnected {
+		h.mu.Unlock()
+		return ErrDisconnected
+	}
+	if err := failure(h.publishErrs, subject); err != nil {
+		h.mu.Unlock()
+		return err
+	}
+	h.published[subject] = append(h.published[subject], message)
+	close(h.updated)
+	h.updated = make(chan struct{})
+	targets := h.pickSubscriptions(subject)
+	h.mu.Unlock()
+
+	for _, s := range targets {
+		s.deliver(message)
+	}
+	return nil
+}
+
+// pickSubscriptions returns one subscription of every queue subscribed to the subject, taking turns. h.mu must be held.
+func (h *Handler) pickSubscriptions(subject string) []*subscription {
+	queues := make(map[string][]*subscription)
+	var order []string
+	for _, s := range h.subs {
+		if !subjectMatches(s.subject, subject) {
+			continue
+		}
+		key := s.subject + "::" + s.queue
+		if _, ok := queues[key]; !ok {
+			order = append(order, key)
+		}
+		queues[key] = append(queues[key], s)
+	}
+
+	targets := make([]*subscription, 0, len(order))
+	for _, key := range order {
+		candidates := queues[key]
+		targets = append(targets, candidates[h.turns[key]%len(candidates)])
+		h.turns[key]++
+	}
+	return targets
+}
+
+// PublishWithChannel publishes the messages received on the channel until it is closed.
+func (h *Handler) PublishWithChannel(subject string, msgch chan *broker.Message) error {
+	go func() {
+		for message := range msgch {
+			_ = h.Publish(subject, message)
+		}
+	}()
+	return nil
+}
+
+// Subscribe blocks until a message is published on the subject, and copies its JSON encoding into message.
+// It returns ErrUnsubscribed if the subscription is removed first, by Unsubscribe, Reset or CloseConnection.
+func (h *Handler) Subscribe(subject, queue string, message []byte) error {
+	received := make(chan *broker.Message, 1)
+	s, err := h.subscribe(subject, queue, func(m *broker.Message) {
+		select {
+		case received <- m:
+		default:
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer h.remove(s)
+
+	var m *broker.Message
+	select {
+	case m = <-received:
+	case <-s.done:
+		return ErrUnsubscribed
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	copy(message, data)
+	return nil
+}
+
+// SubscribeWithChannel sends the messages published on the subject to the channel.
+func (h *Handler) SubscribeWithChannel(subject, queue string, msgch chan *broker.Message) error {
+	_, err := h.subscribe(subject, queue, func(m *broker.Message) { msgch <- m })
+	return err
+}
+
+// SubscribeContext passes the messages published on the subject to the handler, until ctx is done or the
+// subscription is unsubscribed.
+func (h *Handler) SubscribeContext(ctx context.Context, subject, queue string, handler broker.MessageHandler) (broker.Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s, err := h.subscribe(subject, queue, handler)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = s.Unsubscribe()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+func (h *Handler) subscribe(subject, queue string, handler broker.MessageHandler) (*subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.disconnected {
+		return nil, ErrDisconnected
+	}
+	if err := failure(h.subscribeErrs, subject); err != nil {
+		return nil, err
+	}
+	s := &subscription{h: h, subject: subject, queue: queue, handler: handler, done: make(chan struct{})}
+	h.subs = append(h.subs, s)
+	return s, nil
+}
+
+// remove removes the subscription, reporting whether it was still subscribed.
+func (h *Handler) remove(s *subscription) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.subs {
+		if h.subs[i] == s {
+			h.subs = append(h.subs[:i], h.subs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Unsubscribe removes every subscription and request handler of the subject.
+func (h *Handler) Unsubscribe(subject string) error {
+	h.mu.Lock()
+	var removed []*subscription
+	kept := h.subs[:0]
+	for _, s := range h.subs {
+		if s.subject == subject {
+			removed = append(removed, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	h.subs = kept
+	delete(h.responders, subject)
+	h.mu.Unlock()
+
+	for _, s := range removed {
+		s.stop()
+	}
+	return nil
+}
+
+// Request records the request and calls the handler of one of the queues handling requests for the subject.
+// The queues take turns in the order of their names. Handlers registered with wildcards handle the matching subjects.
+func (h *Handler) Request(ctx context.Context, subject string, message *broker.Message) (*broker.Message, error) {
+	message, err := h.codecs.Normalize(message)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	if h.disconnected {
+		h.mu.Unlock()
+		return nil, ErrDisconnected
+	}
+	if err := failure(h.publishErrs, subject); err != nil {
+		h.mu.Unlock()
+		return nil, err
+	}
+	h.requests[subject] = append(h.requests[subject], message)
+	handler := h.pickResponder(subject)
+	h.mu.Unlock()
+
+	if handler == nil {
+		return nil, ErrNoResponders
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	reply, err := h.codecs.Normalize(broker.Respond(handler, message))
+	if err != nil {
+		return nil, err
+	}
+	return reply, broker.ReplyError(reply)
+}
+
+// pickResponder returns the handler of the queue whose turn it is to reply on the subject, or nil.
+// Handlers are registered for subjects which may contain wildcards, their queues take turns in the order of their
+// `subject::queue` keys. h.mu must be held.
+func (h *Handler) pickResponder(subject string) broker.ReplyHandler {
+	handlers := make(map[string]broker.ReplyHandler)
+	for pattern, responders := range h.responders {
+		if !subjectMatches(pattern, subject) {
+			continue
+		}
+		for queue, handler := range responders {
+			handlers[pattern+"::"+queue] = handler
+		}
+	}
+	if len(handlers) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(handlers))
+	for key := range handlers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	key := keys[h.replies[subject]%len(keys)]
+	h.replies[subject]++
+	return handlers[key]
+}
+
+// HandleRequest registers the handler replying to the requests sent on the subject.
+// A handler registered again with the same subject and queue replaces the previous one.
+func (h *Handler) HandleRequest(subject, queue string, handler broker.ReplyHandler) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.disconnected {
+		return ErrDisconnected
+	}
+	if err := failure(h.subscribeErrs, subject); err != nil {
+		return err
+	}
+	if h.responders[subject] == nil {
+		h.responders[subject] = make(map[string]broker.ReplyHandler)
+	}
+	h.responders[subject][queue] = handler
+	return nil
+}
+
+func (h *Handler) Info() string {
+	return h.name
+}
+
+// DeepCopyObject returns the handler itself, as the copies of the other handlers share their connection.
+func (h *Handler) DeepCopyObject() broker.Handler {
+	return h
+}
+
+func (h *Handler) DeepCopyInto(broker.Handler) {}
+
+// IsEmpty reports whether the handler has no subscriptions and no request handlers.
+func (h *Handler) IsEmpty() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs) == 0 && len(h.responders) == 0
+}
+
+// CloseConnection removes every subscription and request handler, and disconnects the handler.
+// The recorded messages are kept.
+func (h *Handler) CloseConnection() {
+	h.mu.Lock()
+	subs := h.subs
+	h.subs = nil
+	h.responders = make(map[string]map[string]broker.ReplyHandler)
+	h.disconnected = true
+	h.mu.Unlock()
+
+	for _, s := range subs {
+		s.stop()
+	}
+	h.watchers.Notify(broker.ConnectionEvent{State: broker.Closed, Endpoint: h.name})
+}
+
+// ConnectedEndpoints returns the subscribed `subject::queue` pairs, as the channel handler does.
+func (h *Handler) ConnectedEndpoints() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.disconnected {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var endpoints []string
+	for _, s := range h.subs {
+		key := s.subject + "::" + s.queue
+		if !seen[key] {
+			seen[key] = true
+			endpoints = append(endpoints, key)
+		}
+	}
+	return endpoints
+}
+
+func (h *Handler) ConnectionEvents(ctx context.Context) <-chan broker.ConnectionEvent {
+	return h.watchers.Watch(ctx)
+}
+
+func (h *Handler) Health() broker.Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	health := broker.Health{
+		Connected:     !h.disconnected,
+		State:         broker.Connected,
+		Endpoint:      h.name,
+		Subscriptions: len(h.subs),
+		Reconnects:    h.reconnects,
+	}
+	if h.disconnected {
+		health.State = broker.Disconnected
+	} else if h.reconnects > 0 {
+		health.State = broker.Reconnected
+	}
+	return health
+}
+
+func (h *Handler) IsConnected() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.disconnected
+}
+
+// subscription is a subscription of the Handler, and the handle returned by SubscribeContext.
+type subscription struct {
+	h       *Handler
+	subject string
+	queue   string
+	handler broker.MessageHandler
+	once    sync.Once
+	done    chan struct{}
+}
+
+func (s *subscription) deliver(message *broker.Message) {
+	select {
+	case <-s.done:
+	default:
+		s.handler(message)
+	}
+}
+
+func (s *subscription) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+func (s *subscription) Unsubscribe() error {
+	s.h.remove(s)
+	s.stop()
+	return nil
+}
+
+func (s *subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// subjectMatches reports whether the subject matches the pattern, which may contain the NATS wildcards:
+// `*` matches a single token and a trailing `>` matches one or more tokens.
+func subjectMatches(pattern, subject string) bool {
+	if pattern == subject {
+		return true
+	}
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" && i == len(patternTokens)-1 {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
@@ -0,0 +1,251 @@
+package brokertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/meshery/meshkit/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerRecordsAndDeliversSynchronously(t *testing.T) {
+	h := NewHandler(nil)
+
+	var received []*broker.Message
+	_, err := h.SubscribeContext(context.Background(), "meshery.meshsync.>", "server", func(m *broker.Message) {
+		received = append(received, m)
+	})
+	require.NoError(t, err)
+	msgch := make(chan *broker.Message, 1)
+	require.NoError(t, h.SubscribeWithChannel("meshery.meshsync.core", "ui", msgch))
+
+	message := &broker.Message{ObjectType: broker.MeshSync, EventType: broker.Add}
+	require.NoError(t, h.Publish("meshery.meshsync.core", message))
+
+	// Delivered before Publish returned.
+	assert.Equal(t, []*broker.Message{message}, received)
+	assert.Same(t, message, <-msgch)
+	assert.Equal(t, []*broker.Message{message}, h.Published("meshery.meshsync.core"))
+	assert.ElementsMatch(t, []string{"meshery.meshsync.>::server", "meshery.meshsync.core::ui"}, h.ConnectedEndpoints())
+}
+
+func TestHandlerQueueTakesTurns(t *testing.T) {
+	h := NewHandler(nil)
+
+	var first, second int
+	_, err := h.SubscribeContext(context.Background(), "exec.input", "q", func(*broker.Message) { first++ })
+	require.NoError(t, err)
+	_, err = h.SubscribeContext(context.Background(), "exec.input", "q", func(*broker.Message) { second++ })
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, h.Publish("exec.input", &broker.Message{}))
+	}
+	assert.Equal(t, 2, first)
+	assert.Equal(t, 2, second)
+}
+
+func TestHandlerAwaitMessage(t *testing.T) {
+	h := NewHandler(nil)
+
+	go func() {
+		for _, object := range []string{"first", "second"} {
+			_ = h.Publish("exec.output", &broker.Message{ObjectType: broker.ExecOutputObject, Object: object})
+		}
+	}()
+
+	m, err := h.AwaitMessage("exec.output", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "first", m.Object)
+	m, err = h.AwaitMessage("exec.output", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "second", m.Object)
+
+	_, err = h.AwaitMessage("exec.output", 10*time.Millisecond)
+	assert.Error(t, err)
+	assert.Len(t, h.Published("exec.output"), 2)
+}
+
+func TestHandlerInjectedFailures(t *testing.T) {
+	h := NewHandler(nil)
+	errPublish := errors.New("publish failed")
+	errSubscribe := errors.New("subscribe failed")
+
+	h.FailPublish("meshery.meshsync", errPublish)
+	assert.ErrorIs(t, h.Publish("meshery.meshsync", &broker.Message{}), errPublish)
+	assert.NoError(t, h.Publish("other", &broker.Message{}))
+	assert.Empty(t, h.Published("meshery.meshsync"))
+	h.FailPublish("meshery.meshsync", nil)
+	assert.NoError(t, h.Publish("meshery.meshsync", &broker.Message{}))
+
+	h.FailSubscribe("", errSubscribe)
+	assert.ErrorIs(t, h.SubscribeWithChannel("any", "q", make(chan *broker.Message)), errSubscribe)
+	_, err := h.SubscribeContext(context.Background(), "any", "q", func(*broker.Message) {})
+	assert.ErrorIs(t, err, errSubscribe)
+	assert.ErrorIs(t, h.HandleRequest("any", "q", nil), errSubscribe)
+}
+
+func TestHandlerDisconnect(t *testing.T) {
+	h := NewHandler(nil)
+	msgch := make(chan *broker.Message, 1)
+	require.NoError(t, h.SubscribeWithChannel("subject", "q", msgch))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := h.ConnectionEvents(ctx)
+
+	h.Disconnect(errors.New("connection reset"))
+	assert.False(t, h.IsConnected())
+	assert.Equal(t, broker.Disconnected, h.Health().State)
+	event := <-events
+	assert.Equal(t, broker.Disconnected, event.State)
+	assert.Equal(t, "connection reset", event.Reason)
+	assert.ErrorIs(t, h.Publish("subject", &broker.Message{}), ErrDisconnected)
+	assert.ErrorIs(t, h.SubscribeWithChannel("subject", "q2", msgch), ErrDisconnected)
+	assert.Empty(t, h.ConnectedEndpoints())
+
+	// Subscriptions survive the disconnection.
+	h.Reconnect()
+	assert.True(t, h.IsConnected())
+	assert.Equal(t, broker.Reconnected, (<-events).State)
+	health := h.Health()
+	assert.Equal(t, broker.Health{Connected: true, State: broker.Reconnected, Endpoint: h.Info(), Subscriptions: 1, Reconnects: 1}, health)
+	require.NoError(t, h.Publish("subject", &broker.Message{}))
+	assert.Len(t, msgch, 1)
+}
+
+func TestHandlerSubscriptions(t *testing.T) {
+	h := NewHandler(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := h.SubscribeContext(ctx, "subject", "q1", func(*broker.Message) {})
+	require.NoError(t, err)
+	other, err := h.SubscribeContext(context.Background(), "subject", "q2", func(*broker.Message) {})
+	require.NoError(t, err)
+
+	cancel()
+	<-sub.Done()
+	assert.Equal(t, []string{"subject::q2"}, h.ConnectedEndpoints())
+
+	require.NoError(t, h.Unsubscribe("subject"))
+	<-other.Done()
+	assert.True(t, h.IsEmpty())
+
+	// The recorded messages outlive the subscriptions, until Reset.
+	require.NoError(t, h.Publish("subject", &broker.Message{}))
+	h.CloseConnection()
+	assert.False(t, h.IsConnected())
+	assert.Len(t, h.Published("subject"), 1)
+	h.Reset()
+	assert.True(t, h.IsConnected())
+	assert.Empty(t, h.Published("subject"))
+}
+
+func TestHandlerRequest(t *testing.T) {
+	h := NewHandler(nil)
+	request := &broker.Message{ObjectType: broker.Request, Request: &broker.RequestObject{Entity: broker.ReSyncDiscoveryEntity}}
+
+	_, err := h.Request(context.Background(), "meshsync.resync", request)
+	assert.ErrorIs(t, err, ErrNoResponders)
+
+	require.NoError(t, h.HandleRequest("meshsync.resync", "meshsync", func(req *broker.Message) (*broker.Message, error) {
+		return &broker.Message{ObjectType: broker.MeshSync, EventType: broker.ReSync}, nil
+	}))
+	reply, err := h.Request(context.Background(), "meshsync.resync", request)
+	require.NoError(t, err)
+	assert.Equal(t, broker.ReSync, reply.EventType)
+	assert.Len(t, h.Requests("meshsync.resync"), 2)
+
+	require.NoError(t, h.HandleRequest("meshsync.fail", "meshsync", func(*broker.Message) (*broker.Message, error) {
+		return nil, errors.New("boom")
+	}))
+	reply, err = h.Request(context.Background(), "meshsync.fail", request)
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, broker.ErrorObject, reply.ObjectType)
+}
+
+func TestHandlerRequestQueuesTakeTurns(t *testing.T) {
+	h := NewHandler(nil)
+
+	var replied []string
+	for _, queue := range []string{"b", "a", "c"} {
+		queue := queue
+		require.NoError(t, h.HandleRequest("meshsync.resync", queue, func(*broker.Message) (*broker.Message, error) {
+			replied = append(replied, queue)
+			return &broker.Message{}, nil
+		}))
+	}
+
+	for i := 0; i < 4; i++ {
+		_, err := h.Request(context.Background(), "meshsync.resync", &broker.Message{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a"}, replied)
+}
+
+func TestHandlerRequestWildcard(t *testing.T) {
+	h := NewHandler(nil)
+
+	var entities []string
+	require.NoError(t, h.HandleRequest("meshsync.*", "meshsync", func(req *broker.Message) (*broker.Message, error) {
+		entities = append(entities, string(req.Request.Entity))
+		return &broker.Message{}, nil
+	}))
+
+	_, err := h.Request(context.Background(), "meshsync.resync", &broker.Message{Request: &broker.RequestObject{Entity: broker.ReSyncDiscoveryEntity}})
+	require.NoError(t, err)
+	_, err = h.Request(context.Background(), "meshsync.resync.all", &broker.Message{Request: &broker.RequestObject{Entity: broker.ReSyncDiscoveryEntity}})
+	assert.ErrorIs(t, err, ErrNoResponders)
+	assert.Equal(t, []string{string(broker.ReSyncDiscoveryEntity)}, entities)
+}
+
+func TestHandlerSubscribe(t *testing.T) {
+	h := NewHandler(nil)
+
+	buf := make([]byte, 256)
+	done := make(chan error)
+	go func() { done <- h.Subscribe("subject", "q", buf) }()
+	require.Eventually(t, func() bool { return !h.IsEmpty() }, time.Second, time.Millisecond)
+
+	require.NoError(t, h.Publish("subject", &broker.Message{ObjectType: broker.MeshSync}))
+	require.NoError(t, <-done)
+	assert.Contains(t, string(buf), `"ObjectType":"meshsync-data"`)
+	assert.True(t, h.IsEmpty())
+}
+
+func TestHandlerSubscribeUnsubscribed(t *testing.T) {
+	h := NewHandler(nil)
+
+	done := make(chan error)
+	go func() { done <- h.Subscribe("subject", "q", make([]byte, 256)) }()
+	require.Eventually(t, func() bool { return !h.IsEmpty() }, time.Second, time.Millisecond)
+
+	require.NoError(t, h.Unsubscribe("subject"))
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrUnsubscribed)
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe did not return after Unsubscribe")
+	}
+}
+
+func TestSubjectMatches(t *testing.T) {
+	tests := []struct {
+		pattern, subject string
+		match            bool
+	}{
+		{"a.b", "a.b", true},
+		{"a.*", "a.b", true},
+		{"a.*", "a.b.c", false},
+		{"a.>", "a.b.c", true},
+		{"a.>", "a", false},
+		{"*.b", "a.b", true},
+		{"a.b", "a.c", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, subjectMatches(tt.pattern, tt.subject), "%s ~ %s", tt.pattern, tt.subject)
+	}
+}
@@ -1,6 +1,8 @@
 package channel
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -8,6 +10,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/meshery/meshkit/broker"
 	"github.com/meshery/meshkit/logger"
+	"go.opentelemetry.io/otel/codes"
 )
 
 type ChannelBrokerHandler struct {
@@ -16,10 +19,30 @@ type ChannelBrokerHandler struct {
 	// this structure represents [subject] => [queue] => channel
 	// so there is a channel per queue per subject
 	storage map[string]map[string]chan *broker.Message
-	mu      sync.RWMutex // protects storage map from concurrent access
-	log     logger.Handler
+	// number of subscriptions consuming each queue channel, so that stopping a subscription
+	// created by SubscribeContext only removes the queue channel once it has no consumers left
+	consumers map[chan *broker.Message]int
+	// [subject] => [queue] => delivered and dropped messages
+	counters map[string]map[string]*queueCounters
+	// [subject] => [queue] => channel of the requests handled by the queue
+	responders map[string]map[string]chan channelRequest
+	mu         sync.RWMutex // protects storage, consumers, counters and responders maps from concurrent access
+	log        logger.Handler
+	watchers   broker.ConnectionWatchers
 }
 
+// channelRequest is a request along with the inbox the reply is sent to.
+type channelRequest struct {
+	message *broker.Message
+	inbox   chan *broker.Message
+}
+
+// messagingSystem identifies the channel handler in the attributes of the spans of the messages.
+const messagingSystem = "channel"
+
+// ErrNoResponders is returned by Request when no handler is registered for the subject.
+var ErrNoResponders = errors.New("no responders available for request")
+
 func NewChannelBrokerHandler(optsSetters ...OptionsSetter) *ChannelBrokerHandler {
 	options := DefaultOptions
 	for _, setOptions := range optsSetters {
@@ -47,9 +70,12 @@ func NewChannelBrokerHandler(optsSetters ...OptionsSetter) *ChannelBrokerHandler
 			"channel-broker-handler--%s",
 			uuid.Must(uuid.NewV4()).String(),
 		),
-		Options: options,
-		storage: make(map[string]map[string]chan *broker.Message),
-		log:     log,
+		Options:    options,
+		storage:    make(map[string]map[string]chan *broker.Message),
+		consumers:  make(map[chan *broker.Message]int),
+		counters:   make(map[string]map[string]*queueCounters),
+		responders: make(map[string]map[string]chan channelRequest),
+		log:        log,
 	}
 }
 
@@ -86,6 +112,7 @@ func (h *ChannelBrokerHandler) Info() string {
 func (h *ChannelBrokerHandler) CloseConnection() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	defer h.watchers.Notify(broker.ConnectionEvent{State: broker.Closed, Endpoint: h.name})
 
 	for subject, qstorage := range h.storage {
 		for queue, ch := range qstorage {
@@ -95,44 +122,139 @@ func (h *ChannelBrokerHandler) CloseConnection() {
 			// message and skip the close, leaking the delivery goroutine.
 			close(ch)
 			delete(qstorage, queue)
+			delete(h.consumers, ch)
 		}
 		delete(h.storage, subject)
 	}
+	for subject := range h.counters {
+		delete(h.counters, subject)
+	}
+	for subject, qresponders := range h.responders {
+		for queue, ch := range qresponders {
+			close(ch)
+			delete(qresponders, queue)
+		}
+		delete(h.responders, subject)
+	}
 }
 
 // Publish - to publish messages
+// A queue whose buffer is full is handled according to the overflow policy of the subject.
 func (h *ChannelBrokerHandler) Publish(subject string, message *broker.Message) error {
+	message, err := h.Codecs.Normalize(message)
+	if err != nil {
+		return err
+	}
+	// The trace context is carried by the Headers of the message itself.
+	message, span := h.Tracing.StartPublishSpan(messagingSystem, subject, message)
+	defer span.End()
+	policy := h.overflowPolicy(subject)
 	h.mu.RLock()
-	defer h.mu.RUnlock()
 
 	if len(h.storage[subject]) <= 0 {
 		// nobody is listening => not publishing
+		h.mu.RUnlock()
 		return nil
 	}
 
 	var successList []string
 	var failedList []string
+	var slowQueues map[string]chan *broker.Message
 
 	for queue, ch := range h.storage[subject] {
-		select {
-		case ch <- message:
+		counters := h.counters[subject][queue]
+		sent, evicted := h.send(ch, message, policy)
+		counters.dropped.Add(evicted)
+		if sent {
+			counters.delivered.Add(1)
 			successList = append(successList, queue)
-		case <-time.After(h.PublishToChannelDelay):
+			continue
+		}
+		counters.dropped.Add(1)
+		switch policy {
+		case OverflowBlock:
 			failedList = append(failedList, queue)
+		case OverflowDisconnect:
+			if slowQueues == nil {
+				slowQueues = make(map[string]chan *broker.Message)
+			}
+			slowQueues[queue] = ch
 		}
 	}
+	h.mu.RUnlock()
+
+	if len(slowQueues) > 0 {
+		h.disconnect(subject, slowQueues)
+	}
 
 	if len(failedList) > 0 {
-		return NewErrChannelBrokerPublish(
+		err := NewErrChannelBrokerPublish(
 			fmt.Errorf("failed to publish to one or more queue for subject %s", subject),
 			successList,
 			failedList,
 		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
 
 	return nil
 }
 
+// send sends the message to the queue channel according to the overflow policy.
+// It reports whether the message was sent, and how many buffered messages were dropped to make room for it.
+func (h *ChannelBrokerHandler) send(ch chan *broker.Message, message *broker.Message, policy OverflowPolicy) (sent bool, evicted uint64) {
+	if policy == OverflowBlock {
+		select {
+		case ch <- message:
+			return true, 0
+		case <-time.After(h.PublishToChannelDelay):
+			return false, 0
+		}
+	}
+
+	for {
+		select {
+		case ch <- message:
+			return true, evicted
+		default:
+		}
+		// An unbuffered channel has nothing to drop.
+		if policy != OverflowDropOldest || cap(ch) == 0 {
+			return false, evicted
+		}
+		// Make room by dropping the oldest buffered message, unless a consumer took it in the meantime.
+		select {
+		case <-ch:
+			evicted++
+		default:
+		}
+	}
+}
+
+// disconnect closes and removes the channels of the slow queues of the subject, which ends their subscriptions.
+// The counters of the queues are kept, so that the dropped messages can still be queried.
+func (h *ChannelBrokerHandler) disconnect(subject string, queues map[string]chan *broker.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for queue, ch := range queues {
+		// The queue might have been removed or replaced since it was found to be slow.
+		if h.storage[subject][queue] != ch {
+			continue
+		}
+		close(ch)
+		delete(h.consumers, ch)
+		delete(h.storage[subject], queue)
+		if h.log != nil {
+			h.log.Info(fmt.Sprintf("disconnected slow consumer of queue %s of subject %s", queue, subject))
+		}
+	}
+	if len(h.storage[subject]) == 0 {
+		delete(h.storage, subject)
+	}
+}
+
 // PublishWithChannel - to publish messages with channel
 func (h *ChannelBrokerHandler) PublishWithChannel(subject string, msgch chan *broker.Message) error {
 	go func() {
@@ -165,30 +287,115 @@ func (h *ChannelBrokerHandler) Subscribe(subject, queue string, message []byte)
 
 // SubscribeWithChannel will publish all the messages received to the given channel
 func (h *ChannelBrokerHandler) SubscribeWithChannel(subject, queue string, msgch chan *broker.Message) error {
+	// a local copy of the channel before starting the goroutine. That way the goroutine never touches the shared map directly
+	ch := h.consume(subject, queue)
+
+	go func(c chan *broker.Message) {
+		for message := range c {
+			// this flow is correct as if we have more than one consumer for one queue
+			// only one will receive the message
+			traced, span := h.Tracing.StartConsumeSpan(messagingSystem, subject, message)
+			msgch <- traced
+			span.End()
+		}
+	}(ch)
+
+	return nil
+}
+
+// SubscribeContext passes the messages received to the handler, until ctx is done or the subscription is unsubscribed.
+// Subscriptions of the same subject and queue share the messages, as with SubscribeWithChannel.
+func (h *ChannelBrokerHandler) SubscribeContext(ctx context.Context, subject, queue string, handler broker.MessageHandler) (broker.Subscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ch := h.consume(subject, queue)
+	sub := &subscription{stop: make(chan struct{}), done: make(chan struct{})}
+
+	go func(c chan *broker.Message) {
+		defer close(sub.done)
+		defer h.release(subject, queue, c)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.stop:
+				return
+			case message, ok := <-c:
+				if !ok {
+					// closed by Unsubscribe(subject) or CloseConnection
+					return
+				}
+				traced, span := h.Tracing.StartConsumeSpan(messagingSystem, subject, message)
+				handler(traced)
+				span.End()
+			}
+		}
+	}(ch)
+
+	return sub, nil
+}
+
+// consume returns the channel of the subject and queue, creating it if needed, and counts one more consumer of it.
+func (h *ChannelBrokerHandler) consume(subject, queue string) chan *broker.Message {
 	h.mu.Lock()
+	defer h.mu.Unlock()
 
 	if h.storage[subject] == nil {
 		h.storage[subject] = make(map[string]chan *broker.Message)
 	}
-
 	if h.storage[subject][queue] == nil {
 		h.storage[subject][queue] = make(chan *broker.Message, h.SingleChannelBufferSize)
 	}
-	// a local copy of the channel before starting the goroutine. That way the goroutine never touches the shared map directly
 	ch := h.storage[subject][queue]
-	h.mu.Unlock()
+	h.consumers[ch]++
+	h.queueCounters(subject, queue)
+	return ch
+}
 
-	go func(c chan *broker.Message) {
-		for message := range c {
-			// this flow is correct as if we have more than one consumer for one queue
-			// only one will receive the message
-			msgch <- message
-		}
-	}(ch)
+// release counts one consumer less of the channel, and removes the channel once it has no consumers left,
+// so that publishing does not wait on a queue nobody reads from.
+func (h *ChannelBrokerHandler) release(subject, queue string, ch chan *broker.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
+	// The channel is gone already if it was closed by Unsubscribe(subject) or CloseConnection.
+	if h.storage[subject][queue] != ch {
+		return
+	}
+	h.consumers[ch]--
+	if h.consumers[ch] > 0 {
+		return
+	}
+	close(ch)
+	delete(h.consumers, ch)
+	delete(h.storage[subject], queue)
+	if len(h.storage[subject]) == 0 {
+		delete(h.storage, subject)
+	}
+	delete(h.counters[subject], queue)
+	if len(h.counters[subject]) == 0 {
+		delete(h.counters, subject)
+	}
+}
+
+// subscription is the handle of a subscription created by SubscribeContext.
+type subscription struct {
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Unsubscribe stops the subscription, leaving the other subscriptions on the subject untouched.
+func (s *subscription) Unsubscribe() error {
+	s.once.Do(func() { close(s.stop) })
 	return nil
 }
 
+func (s *subscription) Done() <-chan struct{} {
+	return s.done
+}
+
 // Unsubscribe closes and removes every queue channel registered for the subject,
 // which ends the delivery goroutines started by SubscribeWithChannel. It is a
 // no-op for a subject with no subscriptions and is safe to call more than once.
@@ -196,17 +403,92 @@ func (h *ChannelBrokerHandler) Unsubscribe(subject string) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	qstorage, ok := h.storage[subject]
-	if !ok {
-		return nil
-	}
-	for queue, ch := range qstorage {
+	for queue, ch := range h.storage[subject] {
 		// Safe to close directly (see CloseConnection): held under h.mu and the
 		// key is deleted after closing, so a channel is never closed twice.
 		close(ch)
-		delete(qstorage, queue)
+		delete(h.storage[subject], queue)
+		delete(h.consumers, ch)
 	}
 	delete(h.storage, subject)
+	delete(h.counters, subject)
+	for queue, ch := range h.responders[subject] {
+		close(ch)
+		delete(h.responders[subject], queue)
+	}
+	delete(h.responders, subject)
+	return nil
+}
+
+// Request sends the message to every queue handling requests for the subject and returns the first reply.
+// Each request carries its own inbox channel, to which the handler sends the reply.
+func (h *ChannelBrokerHandler) Request(ctx context.Context, subject string, message *broker.Message) (*broker.Message, error) {
+	message, err := h.Codecs.Normalize(message)
+	if err != nil {
+		return nil, err
+	}
+	message, span := h.Tracing.StartPublishSpan(messagingSystem, subject, message.WithContext(ctx))
+	defer span.End()
+
+	h.mu.RLock()
+	queues := h.responders[subject]
+	if len(queues) == 0 {
+		h.mu.RUnlock()
+		return nil, ErrNoResponders
+	}
+	// Buffered for every queue, so that late replies never block the handlers.
+	inbox := make(chan *broker.Message, len(queues))
+	sent := 0
+	for _, ch := range queues {
+		select {
+		case ch <- channelRequest{message: message, inbox: inbox}:
+			sent++
+		case <-ctx.Done():
+		case <-time.After(h.PublishToChannelDelay):
+		}
+	}
+	// The read lock is held while sending, so that the request channels are not closed concurrently.
+	h.mu.RUnlock()
+	if sent == 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoResponders
+	}
+
+	select {
+	case reply := <-inbox:
+		return reply, broker.ReplyError(reply)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// HandleRequest replies to the requests sent to the subject and queue with the result of the handler.
+// Handlers registered with the same subject and queue share the requests.
+func (h *ChannelBrokerHandler) HandleRequest(subject, queue string, handler broker.ReplyHandler) error {
+	h.mu.Lock()
+	if h.responders[subject] == nil {
+		h.responders[subject] = make(map[string]chan channelRequest)
+	}
+	if h.responders[subject][queue] == nil {
+		h.responders[subject][queue] = make(chan channelRequest, h.SingleChannelBufferSize)
+	}
+	ch := h.responders[subject][queue]
+	h.mu.Unlock()
+
+	go func(c chan channelRequest) {
+		for request := range c {
+			traced, span := h.Tracing.StartConsumeSpan(messagingSystem, subject, request.message)
+			reply, err := h.Codecs.Normalize(broker.Respond(handler, traced))
+			span.End()
+			if err != nil {
+				reply = broker.ErrorReply(err)
+			}
+			request.inbox <- reply
+		}
+	}(ch)
+
 	return nil
 }
 
@@ -237,7 +519,7 @@ func (h *ChannelBrokerHandler) DeepCopyObject() broker.Handler {
 func (h *ChannelBrokerHandler) IsEmpty() bool {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	return len(h.storage) <= 0
+	return len(h.storage) <= 0 && len(h.responders) <= 0
 }
 
 // IsConnected reports whether this in-process channel broker is usable. It has
@@ -246,3 +528,31 @@ func (h *ChannelBrokerHandler) IsEmpty() bool {
 func (h *ChannelBrokerHandler) IsConnected() bool {
 	return h != nil
 }
+
+// ConnectionEvents returns the channel the connection events are sent to, until ctx is done.
+// The in-process handler has no connection to lose, it only reports CloseConnection as closed.
+func (h *ChannelBrokerHandler) ConnectionEvents(ctx context.Context) <-chan broker.ConnectionEvent {
+	return h.watchers.Watch(ctx)
+}
+
+// Health returns a snapshot of the handler: the messages pending in the queue channels and the number of
+// queues and request handlers as subscriptions. The handler is always connected, see IsConnected.
+func (h *ChannelBrokerHandler) Health() broker.Health {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	health := broker.Health{Connected: true, State: broker.Connected, Endpoint: h.name}
+	for _, qstorage := range h.storage {
+		for _, ch := range qstorage {
+			health.PendingMessages += len(ch)
+			health.Subscriptions++
+		}
+	}
+	for _, qresponders := range h.responders {
+		for _, ch := range qresponders {
+			health.PendingMessages += len(ch)
+			health.Subscriptions++
+		}
+	}
+	return health
+}
@@ -1,6 +1,8 @@
 package channel
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -9,6 +11,10 @@ import (
 	"github.com/meshery/meshkit/broker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestNewChannelBrokerHandler(t *testing.T) {
@@ -363,6 +369,163 @@ func TestChannelBrokerHandler_Publish_Timeout(t *testing.T) {
 	t.Fatal("Expected timeout error but none occurred")
 }
 
+func TestChannelBrokerHandler_Publish_OverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy       OverflowPolicy
+		expected     QueueStats
+		received     []string
+		disconnected bool
+	}{
+		{
+			policy:   OverflowDropNewest,
+			expected: QueueStats{Delivered: 2, Dropped: 2},
+			received: []string{"message-0", "message-1"},
+		},
+		{
+			policy:   OverflowDropOldest,
+			expected: QueueStats{Delivered: 4, Dropped: 2},
+			received: []string{"message-0", "message-3"},
+		},
+		{
+			// message-3 is not counted, as nobody is listening once the consumer is disconnected
+			policy:       OverflowDisconnect,
+			expected:     QueueStats{Delivered: 2, Dropped: 1},
+			received:     []string{"message-0", "message-1"},
+			disconnected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			const subject = "log-stream"
+			handler := NewChannelBrokerHandler(
+				WithSingleChannelBufferSize(1),
+				WithSubjectOverflowPolicy(subject, tt.policy),
+			)
+
+			// The handler holds the first message until the gate is opened, so that the buffer fills up.
+			started := make(chan struct{})
+			gate := make(chan struct{})
+			var received []string
+			sub, err := handler.SubscribeContext(context.Background(), subject, "slow", func(message *broker.Message) {
+				if len(received) == 0 {
+					close(started)
+					<-gate
+				}
+				received = append(received, message.Object.(string))
+			})
+			require.NoError(t, err)
+
+			for i := 0; i < 4; i++ {
+				require.NoError(t, handler.Publish(subject, &broker.Message{Object: fmt.Sprintf("message-%d", i)}))
+				if i == 0 {
+					<-started
+				}
+			}
+
+			stats, ok := handler.QueueStats(subject, "slow")
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, stats)
+			assert.Equal(t, map[string]QueueStats{subject + "::slow": tt.expected}, handler.Stats())
+
+			close(gate)
+			if tt.disconnected {
+				<-sub.Done()
+				assert.Empty(t, handler.ConnectedEndpoints())
+			} else {
+				require.Eventually(t, func() bool {
+					handler.mu.RLock()
+					defer handler.mu.RUnlock()
+					return len(handler.storage[subject]["slow"]) == 0
+				}, time.Second, time.Millisecond)
+				require.NoError(t, sub.Unsubscribe())
+				<-sub.Done()
+			}
+			assert.Equal(t, tt.received, received)
+		})
+	}
+}
+
+func TestChannelBrokerHandler_Publish_BlockCountsDropped(t *testing.T) {
+	handler := NewChannelBrokerHandler(
+		WithPublishToChannelDelay(time.Millisecond),
+		WithSingleChannelBufferSize(1),
+		WithOverflowPolicy(OverflowDropNewest),
+		WithSubjectOverflowPolicy("blocking-subject", OverflowBlock),
+	)
+	assert.Equal(t, OverflowDropNewest, handler.overflowPolicy("other-subject"))
+	assert.Nil(t, DefaultOptions.SubjectOverflowPolicies, "setters must not modify the default options")
+
+	msgch := make(chan *broker.Message)
+	require.NoError(t, handler.SubscribeWithChannel("blocking-subject", "q", msgch))
+	var failed int
+	for i := 0; i < 3; i++ {
+		if err := handler.Publish("blocking-subject", &broker.Message{}); err != nil {
+			failed++
+		}
+	}
+	require.NotZero(t, failed)
+
+	stats, ok := handler.QueueStats("blocking-subject", "q")
+	require.True(t, ok)
+	assert.Equal(t, uint64(failed), stats.Dropped)
+	assert.Equal(t, uint64(3-failed), stats.Delivered)
+
+	// Counters are removed along with the queue.
+	require.NoError(t, handler.Unsubscribe("blocking-subject"))
+	_, ok = handler.QueueStats("blocking-subject", "q")
+	assert.False(t, ok)
+}
+
+func TestChannelBrokerHandler_Publish_Codecs(t *testing.T) {
+	type logLine struct {
+		Line string `json:"line"`
+	}
+	codecs := broker.NewCodecRegistry()
+	broker.RegisterType[logLine](codecs, broker.LogStreamObject, broker.JSONCodec)
+	handler := NewChannelBrokerHandler(WithCodecs(codecs))
+
+	msgch := make(chan *broker.Message, 1)
+	require.NoError(t, handler.SubscribeWithChannel("logs", "q", msgch))
+	require.NoError(t, handler.Publish("logs", &broker.Message{
+		ObjectType: broker.LogStreamObject,
+		Object:     map[string]interface{}{"line": "ready"},
+	}))
+
+	select {
+	case got := <-msgch:
+		assert.Equal(t, &logLine{Line: "ready"}, got.Object)
+	case <-time.After(time.Second):
+		t.Fatal("expected a message")
+	}
+}
+
+func TestChannelBrokerHandler_Health(t *testing.T) {
+	handler := NewChannelBrokerHandler(WithSingleChannelBufferSize(4))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := handler.ConnectionEvents(ctx)
+
+	// The subscription holds the first message, the second one is pending in the queue channel.
+	msgch := make(chan *broker.Message)
+	require.NoError(t, handler.SubscribeWithChannel("subject", "q", msgch))
+	require.NoError(t, handler.HandleRequest("request", "q", func(m *broker.Message) (*broker.Message, error) { return m, nil }))
+	require.NoError(t, handler.Publish("subject", &broker.Message{}))
+	require.NoError(t, handler.Publish("subject", &broker.Message{}))
+	require.Eventually(t, func() bool { return handler.Health().PendingMessages == 1 }, time.Second, time.Millisecond)
+
+	health := handler.Health()
+	assert.True(t, health.Connected)
+	assert.Equal(t, broker.Connected, health.State)
+	assert.Equal(t, 2, health.Subscriptions)
+
+	handler.CloseConnection()
+	event := <-events
+	assert.Equal(t, broker.Closed, event.State)
+	assert.Equal(t, handler.Info(), event.Endpoint)
+	assert.Zero(t, handler.Health().Subscriptions)
+}
+
 func TestChannelBrokerHandler_Unsubscribe(t *testing.T) {
 	handler := NewChannelBrokerHandler()
 	const subject = "test-subject"
@@ -397,3 +560,177 @@ func TestChannelBrokerHandler_Unsubscribe(t *testing.T) {
 	require.NoError(t, handler.Unsubscribe(subject))
 	require.NoError(t, handler.Unsubscribe("no-such-subject"))
 }
+
+func TestChannelBrokerHandler_SubscribeContext(t *testing.T) {
+	handler := NewChannelBrokerHandler()
+	const subject = "test-subject"
+
+	received := make(chan *broker.Message, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, err := handler.SubscribeContext(ctx, subject, "q1", func(message *broker.Message) {
+		received <- message
+	})
+	require.NoError(t, err)
+	other := make(chan *broker.Message, 4)
+	require.NoError(t, handler.SubscribeWithChannel(subject, "q2", other))
+
+	require.NoError(t, handler.Publish(subject, &broker.Message{Object: "first"}))
+	select {
+	case got := <-received:
+		assert.Equal(t, "first", got.Object)
+	case <-time.After(time.Second):
+		t.Fatal("expected a message on the context subscription")
+	}
+	<-other
+
+	// Stopping the subscription removes its queue only.
+	require.NoError(t, sub.Unsubscribe())
+	select {
+	case <-sub.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscription did not stop")
+	}
+	require.NoError(t, sub.Unsubscribe())
+	assert.Equal(t, []string{subject + "::q2"}, handler.ConnectedEndpoints())
+
+	require.NoError(t, handler.Publish(subject, &broker.Message{Object: "second"}))
+	select {
+	case got := <-other:
+		assert.Equal(t, "second", got.Object)
+	case <-time.After(time.Second):
+		t.Fatal("expected the other subscription to keep receiving")
+	}
+	assert.Empty(t, received)
+}
+
+func TestChannelBrokerHandler_SubscribeContext_SharedQueue(t *testing.T) {
+	handler := NewChannelBrokerHandler()
+	const subject = "test-subject"
+
+	received := make(chan string, 4)
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	sub1, err := handler.SubscribeContext(ctx1, subject, "q", func(*broker.Message) { received <- "sub1" })
+	require.NoError(t, err)
+	sub2, err := handler.SubscribeContext(context.Background(), subject, "q", func(*broker.Message) { received <- "sub2" })
+	require.NoError(t, err)
+
+	// Cancelling the context of one consumer of the queue keeps the queue for the other.
+	cancel1()
+	select {
+	case <-sub1.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscription did not stop on context cancellation")
+	}
+	require.NoError(t, handler.Publish(subject, &broker.Message{}))
+	select {
+	case got := <-received:
+		assert.Equal(t, "sub2", got)
+	case <-time.After(time.Second):
+		t.Fatal("expected the remaining consumer to receive the message")
+	}
+
+	// Unsubscribe(subject) stops the remaining subscription too.
+	require.NoError(t, handler.Unsubscribe(subject))
+	select {
+	case <-sub2.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscription did not stop on Unsubscribe(subject)")
+	}
+	assert.True(t, handler.IsEmpty())
+
+	// An already done context is rejected.
+	_, err = handler.SubscribeContext(ctx1, subject, "q", func(*broker.Message) {})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestChannelBrokerHandler_Request(t *testing.T) {
+	handler := NewChannelBrokerHandler()
+	const subject = "exec.request"
+
+	require.NoError(t, handler.HandleRequest(subject, "q1", func(request *broker.Message) (*broker.Message, error) {
+		if request.Request.Entity != broker.ExecRequestEntity {
+			return nil, errors.New("unsupported request")
+		}
+		return &broker.Message{ObjectType: broker.ExecOutputObject, Object: request.Request.Payload}, nil
+	}))
+	assert.False(t, handler.IsEmpty())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	reply, err := handler.Request(ctx, subject, &broker.Message{
+		ObjectType: broker.Request,
+		Request:    &broker.RequestObject{Entity: broker.ExecRequestEntity, Payload: "ls"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, broker.ExecOutputObject, reply.ObjectType)
+	assert.Equal(t, "ls", reply.Object)
+
+	// Handler errors are sent back as error replies.
+	reply, err = handler.Request(ctx, subject, &broker.Message{
+		ObjectType: broker.Request,
+		Request:    &broker.RequestObject{Entity: broker.LogRequestEntity},
+	})
+	require.EqualError(t, err, "unsupported request")
+	assert.Equal(t, broker.ErrorObject, reply.ObjectType)
+
+	// Unsubscribe removes the handler.
+	require.NoError(t, handler.Unsubscribe(subject))
+	assert.True(t, handler.IsEmpty())
+	_, err = handler.Request(ctx, subject, &broker.Message{})
+	require.ErrorIs(t, err, ErrNoResponders)
+}
+
+func TestChannelBrokerHandler_Request_ContextDone(t *testing.T) {
+	handler := NewChannelBrokerHandler()
+	const subject = "resync"
+	release := make(chan struct{})
+	defer close(release)
+
+	require.NoError(t, handler.HandleRequest(subject, "q1", func(*broker.Message) (*broker.Message, error) {
+		<-release
+		return &broker.Message{}, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := handler.Request(ctx, subject, &broker.Message{Request: &broker.RequestObject{Entity: broker.ReSyncDiscoveryEntity}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestChannelBrokerHandler_TracePropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	handler := NewChannelBrokerHandler(WithTracing(broker.Tracing{TracerProvider: provider, Propagator: propagation.TraceContext{}}))
+	const subject = "meshery.meshsync"
+	received := make(chan trace.SpanContext, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, err := handler.SubscribeContext(ctx, subject, "q1", func(message *broker.Message) {
+		received <- trace.SpanContextFromContext(message.Context())
+	})
+	require.NoError(t, err)
+
+	eventCtx, event := provider.Tracer("test").Start(context.Background(), "operator event")
+	require.NoError(t, handler.Publish(subject, (&broker.Message{Object: "event"}).WithContext(eventCtx)))
+	event.End()
+
+	var consumer trace.SpanContext
+	select {
+	case consumer = <-received:
+	case <-time.After(time.Second):
+		t.Fatal("expected a message on the subscription")
+	}
+	require.NoError(t, sub.Unsubscribe())
+	<-sub.Done()
+
+	assert.True(t, consumer.IsValid())
+	assert.Equal(t, event.SpanContext().TraceID(), consumer.TraceID())
+	names := []string{}
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, event.SpanContext().TraceID(), span.SpanContext().TraceID())
+		names = append(names, span.Name())
+	}
+	assert.ElementsMatch(t, []string{"operator event", subject + " publish", subject + " process"}, names)
+}
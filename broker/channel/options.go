@@ -3,19 +3,56 @@ package channel
 import (
 	"time"
 
+	"github.com/meshery/meshkit/broker"
 	"github.com/meshery/meshkit/logger"
 )
 
+// OverflowPolicy decides what Publish does when the buffer of a queue is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock waits up to PublishToChannelDelay for the queue, then reports it in ErrChannelBrokerPublishType.
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropNewest drops the message being published.
+	OverflowDropNewest OverflowPolicy = "drop-newest"
+	// OverflowDropOldest drops the oldest buffered message to make room, so that the buffer acts as a ring buffer.
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowDisconnect drops the message and disconnects the consumers of the queue, ending their subscriptions.
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
 type Options struct {
 	SingleChannelBufferSize uint
 	PublishToChannelDelay   time.Duration
 	Logger                  logger.Handler
+	// OverflowPolicy applies to the subjects without a policy in SubjectOverflowPolicies. Defaults to OverflowBlock.
+	OverflowPolicy OverflowPolicy
+	// SubjectOverflowPolicies sets the policy of individual subjects, e.g. drop-oldest for log streams.
+	SubjectOverflowPolicies map[string]OverflowPolicy
+	// Codecs converts the Object of the messages to the type registered for their ObjectType, so that subscribers
+	// receive the same typed object as from the NATS handler. If nil, objects are passed through as they are, while
+	// the NATS handler delivers generic values: code running on both handlers must pass them the same registry.
+	Codecs *broker.CodecRegistry
+	// Tracing sets the tracer provider and propagator of the spans of the messages. Defaults to the global ones.
+	Tracing broker.Tracing
 }
 
 var DefaultOptions = Options{
 	SingleChannelBufferSize: 1024,
 	PublishToChannelDelay:   1 * time.Second,
 	Logger:                  nil, // Will be created in NewChannelBrokerHandler if nil
+	OverflowPolicy:          OverflowBlock,
+}
+
+// overflowPolicy returns the policy of the subject.
+func (o *Options) overflowPolicy(subject string) OverflowPolicy {
+	if policy, ok := o.SubjectOverflowPolicies[subject]; ok {
+		return policy
+	}
+	if o.OverflowPolicy == "" {
+		return OverflowBlock
+	}
+	return o.OverflowPolicy
 }
 
 type OptionsSetter func(*Options)
@@ -37,3 +74,34 @@ func WithLogger(log logger.Handler) OptionsSetter {
 		o.Logger = log
 	}
 }
+
+func WithOverflowPolicy(policy OverflowPolicy) OptionsSetter {
+	return func(o *Options) {
+		o.OverflowPolicy = policy
+	}
+}
+
+func WithSubjectOverflowPolicy(subject string, policy OverflowPolicy) OptionsSetter {
+	return func(o *Options) {
+		// Copy the map, so that the options the setter is applied to do not share it (e.g. DefaultOptions).
+		policies := make(map[string]OverflowPolicy, len(o.SubjectOverflowPolicies)+1)
+		for s, p := range o.SubjectOverflowPolicies {
+			policies[s] = p
+		}
+		policies[subject] = policy
+		o.SubjectOverflowPolicies = policies
+	}
+}
+
+// WithCodecs sets the codec registry, which should be the one given to the NATS handler, see Options.Codecs.
+func WithCodecs(codecs *broker.CodecRegistry) OptionsSetter {
+	return func(o *Options) {
+		o.Codecs = codecs
+	}
+}
+
+func WithTracing(tracing broker.Tracing) OptionsSetter {
+	return func(o *Options) {
+		o.Tracing = tracing
+	}
+}
@@ -0,0 +1,61 @@
+package channel
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// QueueStats holds the number of messages delivered to and dropped for a queue of a subject.
+// A message is counted as delivered once it is in the buffer of the queue.
+type QueueStats struct {
+	Delivered uint64
+	Dropped   uint64
+}
+
+type queueCounters struct {
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+func (c *queueCounters) stats() QueueStats {
+	return QueueStats{Delivered: c.delivered.Load(), Dropped: c.dropped.Load()}
+}
+
+// QueueStats returns the counters of the queue of the subject.
+// The counters are kept when the consumers of the queue are disconnected by the OverflowDisconnect policy,
+// and removed along with the queue otherwise.
+func (h *ChannelBrokerHandler) QueueStats(subject, queue string) (QueueStats, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	c, ok := h.counters[subject][queue]
+	if !ok {
+		return QueueStats{}, false
+	}
+	return c.stats(), true
+}
+
+// Stats returns the counters of every queue, keyed by `subject::queue` as in ConnectedEndpoints.
+func (h *ChannelBrokerHandler) Stats() map[string]QueueStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := make(map[string]QueueStats)
+	for subject, qcounters := range h.counters {
+		for queue, c := range qcounters {
+			stats[fmt.Sprintf("%s::%s", subject, queue)] = c.stats()
+		}
+	}
+	return stats
+}
+
+// queueCounters returns the counters of the queue of the subject, creating them if needed. h.mu must be held for writing.
+func (h *ChannelBrokerHandler) queueCounters(subject, queue string) *queueCounters {
+	if h.counters[subject] == nil {
+		h.counters[subject] = make(map[string]*queueCounters)
+	}
+	if h.counters[subject][queue] == nil {
+		h.counters[subject][queue] = &queueCounters{}
+	}
+	return h.counters[subject][queue]
+}
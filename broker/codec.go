@@ -0,0 +1,218 @@
+package broker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec encodes and decodes the Object of the messages of an ObjectType.
+type Codec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSONCodec encodes objects as JSON, which keeps the encoding of the messages unchanged.
+	JSONCodec Codec = jsonCodec{}
+	// MsgpackCodec encodes objects as MessagePack, using the `json` struct tags of the object.
+	MsgpackCodec Codec = msgpackCodec{}
+	// ProtobufCodec encodes objects implementing proto.Message as protocol buffers.
+	ProtobufCodec Codec = protobufCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                               { return "json" }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string { return "msgpack" }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string { return "protobuf" }
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T does not implement proto.Message", v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// CodecRegistry maps ObjectTypes to the Go type and the Codec of the Object of their messages,
+// so that subscribers receive the same typed object whichever broker delivers the message.
+// A nil *CodecRegistry has no ObjectType registered: objects are encoded as JSON and decoded as generic values.
+type CodecRegistry struct {
+	mu    sync.RWMutex
+	types map[ObjectType]registeredType
+}
+
+type registeredType struct {
+	typ   reflect.Type
+	codec Codec
+}
+
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{types: make(map[ObjectType]registeredType)}
+}
+
+// RegisterType registers T as the type of the Object of the messages of the ObjectType, encoded with the codec.
+// Subscribers receive the Object as a *T.
+func RegisterType[T any](r *CodecRegistry, objectType ObjectType, codec Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[objectType] = registeredType{typ: reflect.TypeOf((*T)(nil)).Elem(), codec: codec}
+}
+
+func (r *CodecRegistry) lookup(objectType ObjectType) (registeredType, bool) {
+	if r == nil {
+		return registeredType{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[objectType]
+	return t, ok
+}
+
+// envelope is the encoding of a Message whose Object is encoded with the codec of its ObjectType.
+// Objects encoded by a binary codec are carried as base64 strings.
+type envelope struct {
+	ObjectType ObjectType
+	EventType  EventType
+	Request    *RequestObject
+	Object     json.RawMessage
+}
+
+// Marshal encodes the message, encoding its Object with the codec registered for its ObjectType.
+func (r *CodecRegistry) Marshal(message *Message) ([]byte, error) {
+	t, ok := r.lookup(message.ObjectType)
+	if !ok || message.Object == nil {
+		return json.Marshal(message)
+	}
+
+	object, err := t.encode(message.Object)
+	if err != nil {
+		return nil, err
+	}
+	if t.codec.Name() != JSONCodec.Name() {
+		if object, err = json.Marshal(object); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(envelope{
+		ObjectType: message.ObjectType,
+		EventType:  message.EventType,
+		Request:    message.Request,
+		Object:     object,
+	})
+}
+
+// Unmarshal decodes a message encoded by Marshal, decoding its Object into the type registered for its ObjectType.
+func (r *CodecRegistry) Unmarshal(data []byte) (*Message, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	t, ok := r.lookup(env.ObjectType)
+	if !ok || len(env.Object) == 0 || string(env.Object) == "null" {
+		var message Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, err
+		}
+		return &message, nil
+	}
+
+	object := []byte(env.Object)
+	if t.codec.Name() != JSONCodec.Name() {
+		if err := json.Unmarshal(env.Object, &object); err != nil {
+			return nil, err
+		}
+	}
+	decoded, err := t.decode(object)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{ObjectType: env.ObjectType, EventType: env.EventType, Request: env.Request, Object: decoded}, nil
+}
+
+// Normalize returns the message with its Object converted to the type registered for its ObjectType, as Unmarshal
+// would decode it. It is used by in-process brokers, which do not encode the messages. An Object which already is a
+// *T is kept as it is, any other value is converted through the codec.
+func (r *CodecRegistry) Normalize(message *Message) (*Message, error) {
+	t, ok := r.lookup(message.ObjectType)
+	if !ok || message.Object == nil || reflect.TypeOf(message.Object) == reflect.PointerTo(t.typ) {
+		return message, nil
+	}
+
+	normalized := *message
+	if v := reflect.ValueOf(message.Object); v.Type() == t.typ {
+		ptr := reflect.New(t.typ)
+		ptr.Elem().Set(v)
+		normalized.Object = ptr.Interface()
+		return &normalized, nil
+	}
+	data, err := t.encode(message.Object)
+	if err != nil {
+		return nil, err
+	}
+	if normalized.Object, err = t.decode(data); err != nil {
+		return nil, err
+	}
+	return &normalized, nil
+}
+
+func (t registeredType) encode(object interface{}) ([]byte, error) {
+	// Codecs such as protobuf require a pointer.
+	if v := reflect.ValueOf(object); v.Type() == t.typ {
+		ptr := reflect.New(t.typ)
+		ptr.Elem().Set(v)
+		object = ptr.Interface()
+	}
+	data, err := t.codec.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s object with the %s codec: %w", t.typ, t.codec.Name(), err)
+	}
+	return data, nil
+}
+
+func (t registeredType) decode(data []byte) (interface{}, error) {
+	object := reflect.New(t.typ).Interface()
+	if err := t.codec.Unmarshal(data, object); err != nil {
+		return nil, fmt.Errorf("failed to decode %s object with the %s codec: %w", t.typ, t.codec.Name(), err)
+	}
+	return object, nil
+}
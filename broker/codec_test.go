@@ -0,0 +1,124 @@
+package broker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type logLine struct {
+	Container string            `json:"container"`
+	Line      string            `json:"line"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+func TestCodecRegistryRoundTrip(t *testing.T) {
+	registry := NewCodecRegistry()
+	RegisterType[logLine](registry, LogStreamObject, MsgpackCodec)
+	RegisterType[logLine](registry, ExecOutputObject, JSONCodec)
+	RegisterType[wrapperspb.StringValue](registry, MeshSync, ProtobufCodec)
+
+	line := logLine{Container: "app", Line: "ready", Labels: map[string]string{"app": "meshery"}}
+	tests := []struct {
+		name    string
+		message *Message
+		check   func(t *testing.T, object interface{})
+	}{
+		{
+			name:    "msgpack",
+			message: &Message{ObjectType: LogStreamObject, EventType: Add, Object: line},
+			check:   func(t *testing.T, object interface{}) { assert.Equal(t, &line, object) },
+		},
+		{
+			name:    "json",
+			message: &Message{ObjectType: ExecOutputObject, EventType: Add, Object: &line},
+			check:   func(t *testing.T, object interface{}) { assert.Equal(t, &line, object) },
+		},
+		{
+			name:    "protobuf",
+			message: &Message{ObjectType: MeshSync, EventType: Update, Object: wrapperspb.String("pod")},
+			check: func(t *testing.T, object interface{}) {
+				require.IsType(t, &wrapperspb.StringValue{}, object)
+				assert.True(t, proto.Equal(wrapperspb.String("pod"), object.(*wrapperspb.StringValue)))
+			},
+		},
+		{
+			name:    "unregistered",
+			message: &Message{ObjectType: SMI, EventType: Add, Object: line},
+			check: func(t *testing.T, object interface{}) {
+				assert.Equal(t, map[string]interface{}{"container": "app", "line": "ready", "labels": map[string]interface{}{"app": "meshery"}}, object)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := registry.Marshal(tt.message)
+			require.NoError(t, err)
+			decoded, err := registry.Unmarshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.message.ObjectType, decoded.ObjectType)
+			assert.Equal(t, tt.message.EventType, decoded.EventType)
+			tt.check(t, decoded.Object)
+		})
+	}
+}
+
+func TestCodecRegistryJSONWireFormat(t *testing.T) {
+	registry := NewCodecRegistry()
+	RegisterType[logLine](registry, LogStreamObject, JSONCodec)
+
+	message := &Message{ObjectType: LogStreamObject, EventType: Add, Object: logLine{Container: "app", Line: "ready"}}
+	expected, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	// The JSON codec keeps the encoding of the messages unchanged, as does a nil registry.
+	for _, r := range []*CodecRegistry{registry, nil} {
+		data, err := r.Marshal(message)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(data))
+	}
+
+	var nilRegistry *CodecRegistry
+	decoded, err := nilRegistry.Unmarshal(expected)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"container": "app", "line": "ready"}, decoded.Object)
+}
+
+func TestCodecRegistryNormalize(t *testing.T) {
+	registry := NewCodecRegistry()
+	RegisterType[logLine](registry, LogStreamObject, MsgpackCodec)
+	line := &logLine{Container: "app", Line: "ready"}
+
+	for name, object := range map[string]interface{}{
+		"pointer": line,
+		"value":   *line,
+		"generic": map[string]interface{}{"container": "app", "line": "ready"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			message := &Message{ObjectType: LogStreamObject, Object: object}
+			normalized, err := registry.Normalize(message)
+			require.NoError(t, err)
+			assert.Equal(t, line, normalized.Object)
+
+			// In-process brokers hand subscribers the same object as brokers encoding the messages.
+			data, err := registry.Marshal(message)
+			require.NoError(t, err)
+			decoded, err := registry.Unmarshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, decoded.Object, normalized.Object)
+		})
+	}
+
+	// A pointer of the registered type is kept as it is.
+	normalized, err := registry.Normalize(&Message{ObjectType: LogStreamObject, Object: line})
+	require.NoError(t, err)
+	assert.Same(t, line, normalized.Object)
+
+	_, err = registry.Normalize(&Message{ObjectType: LogStreamObject, Object: "not a log line"})
+	assert.Error(t, err)
+}
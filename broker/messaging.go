@@ -1,5 +1,10 @@
 package broker
 
+import (
+	"context"
+	"fmt"
+)
+
 var (
 	Request          ObjectType = "request-payload"
 	MeshSync         ObjectType = "meshsync-data"
@@ -31,9 +36,43 @@ type Message struct {
 	EventType  EventType
 	Request    *RequestObject
 	Object     interface{}
+	// Headers are sent alongside the message rather than as part of it: as NATS message headers by the NATS handler,
+	// on the message itself by the channel handler. They carry the W3C trace context, see StartPublishSpan.
+	Headers map[string]string `json:"-"`
+
+	ctx context.Context
 }
 
 type RequestObject struct {
 	Entity  RequestEntity
 	Payload interface{}
 }
+
+// ErrorReply builds the reply sent back to the requester when a ReplyHandler fails.
+func ErrorReply(err error) *Message {
+	return &Message{
+		ObjectType: ErrorObject,
+		EventType:  ErrorEvent,
+		Object:     err.Error(),
+	}
+}
+
+// ReplyError returns the error carried by an error reply, or nil if the reply is not an error reply.
+func ReplyError(reply *Message) error {
+	if reply == nil || reply.ObjectType != ErrorObject {
+		return nil
+	}
+	return fmt.Errorf("%v", reply.Object)
+}
+
+// Respond runs the handler and converts its outcome to the reply sent back to the requester.
+func Respond(handler ReplyHandler, request *Message) *Message {
+	reply, err := handler(request)
+	if err != nil {
+		return ErrorReply(err)
+	}
+	if reply == nil {
+		return &Message{}
+	}
+	return reply
+}
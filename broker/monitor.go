@@ -0,0 +1,96 @@
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type ConnectionState string
+
+const (
+	Connected    ConnectionState = "connected"
+	Disconnected ConnectionState = "disconnected"
+	Reconnected  ConnectionState = "reconnected"
+	Closed       ConnectionState = "closed"
+)
+
+// ConnectionEvent reports a change of the state of the connection of a Handler.
+type ConnectionEvent struct {
+	State ConnectionState
+	// Endpoint is the endpoint the handler is connected to, or was connected to when disconnected.
+	Endpoint string
+	// Reason is the error which caused a disconnection, if any.
+	Reason string
+	Time   time.Time
+}
+
+// Health is a snapshot of the state of the connection of a Handler.
+type Health struct {
+	Connected bool
+	State     ConnectionState
+	Endpoint  string
+	// RTT is the round trip time to the server, if the handler has a server and is connected.
+	RTT time.Duration
+	// PendingMessages and PendingBytes are the messages received but not yet handled by the subscriptions.
+	PendingMessages int
+	PendingBytes    int
+	// BufferedBytes are the bytes published but not yet flushed to the server, e.g. while reconnecting.
+	BufferedBytes int
+	Subscriptions int
+	Reconnects    uint64
+	LastError     string
+}
+
+type MonitorInterface interface {
+	// ConnectionEvents returns the channel the connection events are sent to, until ctx is done.
+	// Events are dropped when the channel is full, rather than blocking the connection.
+	ConnectionEvents(ctx context.Context) <-chan ConnectionEvent
+	// Health returns a snapshot of the state of the connection.
+	Health() Health
+}
+
+// connectionEventsBuffer is the number of events a watcher can fall behind before events are dropped.
+const connectionEventsBuffer = 16
+
+// ConnectionWatchers fans out the connection events of a Handler to the channels returned by Watch.
+// The zero value is ready to use.
+type ConnectionWatchers struct {
+	mu       sync.Mutex
+	watchers map[chan ConnectionEvent]struct{}
+}
+
+// Watch returns a channel receiving the events notified until ctx is done, it is closed then.
+func (w *ConnectionWatchers) Watch(ctx context.Context) <-chan ConnectionEvent {
+	ch := make(chan ConnectionEvent, connectionEventsBuffer)
+	w.mu.Lock()
+	if w.watchers == nil {
+		w.watchers = make(map[chan ConnectionEvent]struct{})
+	}
+	w.watchers[ch] = struct{}{}
+	w.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		delete(w.watchers, ch)
+		close(ch)
+		w.mu.Unlock()
+	}()
+	return ch
+}
+
+// Notify sends the event to every watcher which is not full.
+func (w *ConnectionWatchers) Notify(event ConnectionEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.watchers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionWatchers(t *testing.T) {
+	var watchers ConnectionWatchers
+	ctx, cancel := context.WithCancel(context.Background())
+	events := watchers.Watch(ctx)
+
+	watchers.Notify(ConnectionEvent{State: Disconnected, Endpoint: "nats://localhost:4222", Reason: "EOF"})
+	event := <-events
+	assert.Equal(t, Disconnected, event.State)
+	assert.Equal(t, "EOF", event.Reason)
+	assert.False(t, event.Time.IsZero())
+
+	// A full watcher drops events instead of blocking the connection.
+	for i := 0; i < connectionEventsBuffer+1; i++ {
+		watchers.Notify(ConnectionEvent{State: Reconnected})
+	}
+	assert.Len(t, events, connectionEventsBuffer)
+
+	cancel()
+	for range events {
+	}
+	watchers.Notify(ConnectionEvent{State: Closed})
+}
@@ -1,6 +1,8 @@
 package nats
 
 import (
+	"fmt"
+
 	"github.com/meshery/meshkit/errors"
 )
 
@@ -11,6 +13,8 @@ const (
 	ErrPublishRequestCode = "meshkit-11121"
 	ErrQueueSubscribeCode = "meshkit-11122"
 	ErrUnsubscribeCode    = "meshkit-11327"
+	ErrJetStreamCode      = "meshkit-11336"
+	ErrStreamRequestCode  = "meshkit-11349"
 )
 
 func ErrConnect(err error) error {
@@ -31,3 +35,11 @@ func ErrQueueSubscribe(err error) error {
 func ErrUnsubscribe(err error) error {
 	return errors.New(ErrUnsubscribeCode, errors.Alert, []string{"Unsubscribe failed"}, []string{err.Error()}, []string{"NATS is unhealthy"}, []string{"Make sure NATS is up and running"})
 }
+
+func ErrJetStream(err error) error {
+	return errors.New(ErrJetStreamCode, errors.Alert, []string{"JetStream setup failed"}, []string{err.Error()}, []string{"JetStream is not enabled on the NATS server", "The stream configuration conflicts with an existing stream"}, []string{"Make sure JetStream is enabled on the NATS server", "Check the configuration of the existing streams"})
+}
+
+func ErrStreamRequest(subject, stream string) error {
+	return errors.New(ErrStreamRequestCode, errors.Alert, []string{"Request/reply is not supported on JetStream subjects"}, []string{fmt.Sprintf("Subject %s is captured by the JetStream stream %s, which acknowledges the requests in place of the responders", subject, stream)}, []string{"The subject of the request matches one of the JetStream subject prefixes"}, []string{"Send requests on a subject outside of the JetStream subject prefixes"})
+}
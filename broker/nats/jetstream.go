@@ -0,0 +1,164 @@
+package nats
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/meshery/meshkit/broker"
+	nats "github.com/nats-io/nats.go"
+)
+
+// setupJetStream creates or updates the stream of every subject prefix.
+func (n *Nats) setupJetStream(opts *JetStreamOptions) error {
+	js, err := n.nc.JetStream()
+	if err != nil {
+		return ErrJetStream(err)
+	}
+
+	storage := nats.FileStorage
+	if opts.MemoryStorage {
+		storage = nats.MemoryStorage
+	}
+	for _, prefix := range opts.SubjectPrefixes {
+		cfg := &nats.StreamConfig{
+			Name:     streamName(prefix),
+			Subjects: []string{prefix, prefix + ".>"},
+			MaxAge:   opts.MaxAge,
+			Storage:  storage,
+		}
+		_, err := js.StreamInfo(cfg.Name)
+		switch {
+		case errors.Is(err, nats.ErrStreamNotFound):
+			_, err = js.AddStream(cfg)
+		case err == nil:
+			_, err = js.UpdateStream(cfg)
+		}
+		if err != nil {
+			return ErrJetStream(fmt.Errorf("stream %s: %w", cfg.Name, err))
+		}
+	}
+
+	n.js = js
+	n.jsOpts = opts
+	return nil
+}
+
+// streamFor returns the name of the stream capturing the subject, or "" if JetStream is not used for the subject.
+func (n *Nats) streamFor(subject string) string {
+	if n.js == nil || n.jsOpts == nil {
+		return ""
+	}
+	for _, prefix := range n.jsOpts.SubjectPrefixes {
+		if subject == prefix || strings.HasPrefix(subject, prefix+".") {
+			return streamName(prefix)
+		}
+	}
+	return ""
+}
+
+// streamName derives the name of the stream of a subject prefix, stream names cannot contain `.`, `*` or `>`.
+func streamName(prefix string) string {
+	return strings.ToUpper(jetStreamNameReplacer.Replace(prefix))
+}
+
+// durableName derives the name of the durable consumer of a queue.
+// The subject is part of the name, as a consumer only delivers the subject it was created for.
+func durableName(queue, subject string) string {
+	return jetStreamNameReplacer.Replace(queue + "_" + subject)
+}
+
+var jetStreamNameReplacer = strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_", "\t", "_")
+
+// subscribeJetStream passes the messages of the subject to deliver, acknowledging every message once it has
+// been handed over. With a queue, the messages are consumed through a durable consumer which outlives the subscription,
+// so that the messages published in the meantime are delivered on the next subscription.
+func (n *Nats) subscribeJetStream(stream, subject, queue string, deliver func(*broker.Message) bool) (*nats.Subscription, error) {
+	cb := func(msg *nats.Msg) {
+		parsed, err := n.codecs.Unmarshal(msg.Data)
+		if err != nil {
+			// The message can never be decoded, do not redeliver it.
+			_ = msg.Term()
+			if n.log != nil {
+				n.log.Error(err)
+			} else {
+				log.Printf("failed to decode message: %v", err)
+			}
+			return
+		}
+		if n.deliverTraced(msg, parsed, deliver) {
+			_ = msg.Ack()
+		} else {
+			_ = msg.Nak()
+		}
+	}
+
+	var sub *nats.Subscription
+	var err error
+	if queue == "" {
+		sub, err = n.js.Subscribe(subject, cb, append(n.deliverOpts(), nats.ManualAck(), nats.AckExplicit())...)
+	} else {
+		durable := durableName(queue, subject)
+		if err = n.ensureConsumer(stream, durable, subject, queue); err == nil {
+			// Binding to the consumer keeps it on Unsubscribe, unlike a consumer created by the subscription.
+			sub, err = n.js.QueueSubscribe(subject, queue, cb, nats.Bind(stream, durable), nats.ManualAck())
+		}
+	}
+	if err != nil {
+		if n.log != nil {
+			n.log.Error(err)
+		} else {
+			log.Printf("jetstream subscribe error: %v", err)
+		}
+		return nil, ErrQueueSubscribe(err)
+	}
+	return sub, nil
+}
+
+// ensureConsumer creates the durable push consumer delivering the subject to the queue, unless it exists already.
+func (n *Nats) ensureConsumer(stream, durable, subject, queue string) error {
+	if _, err := n.js.ConsumerInfo(stream, durable); err == nil {
+		return nil
+	} else if !errors.Is(err, nats.ErrConsumerNotFound) {
+		return err
+	}
+
+	cfg := &nats.ConsumerConfig{
+		Durable:        durable,
+		DeliverSubject: nats.NewInbox(),
+		DeliverGroup:   queue,
+		FilterSubject:  subject,
+		AckPolicy:      nats.AckExplicitPolicy,
+		AckWait:        n.jsOpts.AckWait,
+		DeliverPolicy:  nats.DeliverAllPolicy,
+	}
+	switch {
+	case n.jsOpts.StartSequence > 0:
+		cfg.DeliverPolicy = nats.DeliverByStartSequencePolicy
+		cfg.OptStartSeq = n.jsOpts.StartSequence
+	case !n.jsOpts.StartTime.IsZero():
+		startTime := n.jsOpts.StartTime
+		cfg.DeliverPolicy = nats.DeliverByStartTimePolicy
+		cfg.OptStartTime = &startTime
+	}
+	if _, err := n.js.AddConsumer(stream, cfg); err != nil {
+		// Another subscriber of the queue might have created it concurrently.
+		if _, infoErr := n.js.ConsumerInfo(stream, durable); infoErr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// deliverOpts returns the subscription options replaying the stream from the configured position.
+func (n *Nats) deliverOpts() []nats.SubOpt {
+	switch {
+	case n.jsOpts.StartSequence > 0:
+		return []nats.SubOpt{nats.StartSequence(n.jsOpts.StartSequence)}
+	case !n.jsOpts.StartTime.IsZero():
+		return []nats.SubOpt{nats.StartTime(n.jsOpts.StartTime)}
+	}
+	return []nats.SubOpt{nats.DeliverAll()}
+}
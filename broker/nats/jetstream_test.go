@@ -0,0 +1,167 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/meshery/meshkit/broker"
+	"github.com/meshery/meshkit/errors"
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// runJetStreamServer starts an embedded NATS server with JetStream enabled, shut down at the end of the test.
+func runJetStreamServer(t *testing.T) *server.Server {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create the NATS server: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server is not ready for connections")
+	}
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func newJetStreamHandler(t *testing.T, s *server.Server) *Nats {
+	t.Helper()
+	h, err := New(Options{
+		URLS:           []string{s.ClientURL()},
+		ConnectionName: t.Name(),
+		MaxReconnect:   -1,
+		JetStream: &JetStreamOptions{
+			SubjectPrefixes: []string{"meshery.meshsync"},
+			MemoryStorage:   true,
+			AckWait:         time.Second,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(h.CloseConnection)
+	return h.(*Nats)
+}
+
+func receive(t *testing.T, msgch chan *broker.Message) *broker.Message {
+	t.Helper()
+	select {
+	case message := <-msgch:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+		return nil
+	}
+}
+
+func TestJetStreamDurableRedelivery(t *testing.T) {
+	s := runJetStreamServer(t)
+	h := newJetStreamHandler(t, s)
+	subject := "meshery.meshsync.core"
+
+	msgch := make(chan *broker.Message, 10)
+	if err := h.SubscribeWithChannel(subject, "server", msgch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.Publish(subject, &broker.Message{ObjectType: broker.MeshSync, Object: "first"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := receive(t, msgch).Object; got != "first" {
+		t.Fatalf("received %v, want first", got)
+	}
+
+	// Messages published while the queue has no subscriber are kept by its durable consumer.
+	if err := h.Unsubscribe(subject); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.Publish(subject, &broker.Message{ObjectType: broker.MeshSync, Object: "second"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.SubscribeWithChannel(subject, "server", msgch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := receive(t, msgch).Object; got != "second" {
+		t.Fatalf("received %v after resubscribing, want second", got)
+	}
+
+	// The acknowledged messages are not delivered again.
+	select {
+	case message := <-msgch:
+		t.Fatalf("unexpected redelivery of %v", message.Object)
+	case <-time.After(2 * time.Second):
+	}
+}
+
+func TestJetStreamDeliverGroup(t *testing.T) {
+	s := runJetStreamServer(t)
+	subject := "meshery.meshsync.core"
+
+	msgch := make(chan *broker.Message, 100)
+	first, second := newJetStreamHandler(t, s), newJetStreamHandler(t, s)
+	for _, h := range []*Nats{first, second} {
+		if err := h.SubscribeWithChannel(subject, "server", msgch); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	const count = 20
+	for i := 0; i < count; i++ {
+		if err := first.Publish(subject, &broker.Message{ObjectType: broker.MeshSync, Object: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Every message is delivered to a single member of the queue.
+	seen := map[interface{}]bool{}
+	for i := 0; i < count; i++ {
+		object := receive(t, msgch).Object
+		if seen[object] {
+			t.Fatalf("message %v was delivered twice", object)
+		}
+		seen[object] = true
+	}
+	select {
+	case message := <-msgch:
+		t.Fatalf("unexpected delivery of %v", message.Object)
+	case <-time.After(2 * time.Second):
+	}
+}
+
+func TestJetStreamRequest(t *testing.T) {
+	s := runJetStreamServer(t)
+	h := newJetStreamHandler(t, s)
+	handler := func(*broker.Message) (*broker.Message, error) {
+		return &broker.Message{ObjectType: broker.MeshSync, EventType: broker.ReSync}, nil
+	}
+
+	// The stream would acknowledge the request in place of the responder.
+	if err := h.HandleRequest("meshery.meshsync.resync", "meshsync", handler); errors.GetCode(err) != ErrStreamRequestCode {
+		t.Fatalf("HandleRequest on a stream subject = %v, want %s", err, ErrStreamRequestCode)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := h.Request(ctx, "meshery.meshsync.resync", &broker.Message{ObjectType: broker.Request}); errors.GetCode(err) != ErrStreamRequestCode {
+		t.Fatalf("Request on a stream subject = %v, want %s", err, ErrStreamRequestCode)
+	}
+
+	// Other subjects use core NATS request/reply.
+	if err := h.HandleRequest("meshery.resync", "meshsync", handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reply, err := h.Request(ctx, "meshery.resync", &broker.Message{ObjectType: broker.Request})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply.EventType != broker.ReSync {
+		t.Fatalf("reply = %+v, want a %s event", reply, broker.ReSync)
+	}
+}
@@ -0,0 +1,82 @@
+package nats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/meshery/meshkit/broker"
+	nats "github.com/nats-io/nats.go"
+)
+
+// connectionMonitor records the state of the connection reported by the NATS callbacks, and notifies the watchers.
+// It is created before connecting, so that the callbacks of the initial connection are recorded.
+type connectionMonitor struct {
+	watchers broker.ConnectionWatchers
+	mu       sync.Mutex
+	state    broker.ConnectionState
+	endpoint string
+}
+
+func (m *connectionMonitor) notify(nc *nats.Conn, state broker.ConnectionState, reason error) {
+	m.mu.Lock()
+	if endpoint := nc.ConnectedUrlRedacted(); endpoint != "" {
+		m.endpoint = endpoint
+	}
+	m.state = state
+	event := broker.ConnectionEvent{State: state, Endpoint: m.endpoint}
+	m.mu.Unlock()
+
+	if reason != nil {
+		event.Reason = reason.Error()
+	}
+	m.watchers.Notify(event)
+}
+
+func (m *connectionMonitor) current() (broker.ConnectionState, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state, m.endpoint
+}
+
+// ConnectionEvents returns the channel the connection events are sent to, until ctx is done.
+func (n *Nats) ConnectionEvents(ctx context.Context) <-chan broker.ConnectionEvent {
+	if n == nil || n.monitor == nil {
+		ch := make(chan broker.ConnectionEvent)
+		close(ch)
+		return ch
+	}
+	return n.monitor.watchers.Watch(ctx)
+}
+
+// Health returns a snapshot of the state of the connection, including the RTT to the server when connected.
+func (n *Nats) Health() broker.Health {
+	if n == nil || n.nc == nil {
+		return broker.Health{State: broker.Closed}
+	}
+
+	health := broker.Health{
+		Connected:     n.nc.IsConnected(),
+		Subscriptions: n.nc.NumSubscriptions(),
+		Reconnects:    n.nc.Stats().Reconnects,
+	}
+	if n.monitor != nil {
+		health.State, health.Endpoint = n.monitor.current()
+	}
+	if health.State == "" && health.Connected {
+		// The callback of the initial connection is called asynchronously.
+		health.State, health.Endpoint = broker.Connected, n.nc.ConnectedUrlRedacted()
+	}
+	if health.Connected {
+		if rtt, err := n.nc.RTT(); err == nil {
+			health.RTT = rtt
+		}
+	}
+	if buffered, err := n.nc.Buffered(); err == nil {
+		health.BufferedBytes = buffered
+	}
+	if err := n.nc.LastError(); err != nil {
+		health.LastError = err.Error()
+	}
+	health.PendingMessages, health.PendingBytes = n.subs.pending()
+	return health
+}
@@ -2,7 +2,6 @@ package nats
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -36,6 +35,33 @@ type Options struct {
 	// connects as soon as the broker becomes reachable and reconnects if the
 	// broker (or a port-forward in front of it) drops — no restart needed.
 	RetryOnFailedConnect bool
+	// JetStream opts into JetStream for the subjects of its streams: messages published to them are persisted and
+	// SubscribeWithChannel consumes them through durable consumers with explicit acks, so that messages published
+	// while a subscriber is down are delivered once it is back. Other subjects keep using core NATS.
+	JetStream *JetStreamOptions
+	// Codecs encodes and decodes the Object of the messages according to their ObjectType.
+	// If nil, objects are encoded as JSON and decoded as generic values, while the channel handler passes the
+	// published Go values through: code running on both handlers must pass them the same registry.
+	Codecs *broker.CodecRegistry
+	// Tracing sets the tracer provider and propagator of the spans of the messages. Defaults to the global ones.
+	Tracing broker.Tracing
+}
+
+// JetStreamOptions configures the JetStream mode of the NATS handler.
+type JetStreamOptions struct {
+	// SubjectPrefixes lists the subject prefixes a stream is created (or updated) for on connect.
+	// The stream of `meshery.meshsync` is named `MESHERY_MESHSYNC` and captures `meshery.meshsync` and `meshery.meshsync.>`.
+	SubjectPrefixes []string
+	// MaxAge bounds the retention of the messages in the streams. If 0, messages are retained up to the server limits.
+	MaxAge time.Duration
+	// MemoryStorage stores the streams in memory instead of files.
+	MemoryStorage bool
+	// AckWait is the duration after which a message that was not acknowledged is redelivered. If 0, the server default is used.
+	AckWait time.Duration
+	// StartSequence and StartTime replay the stream from the given sequence number or time, instead of from its first message.
+	// They only apply to the consumers created by the handler: an existing durable consumer resumes where it stopped.
+	StartSequence uint64
+	StartTime     time.Time
 }
 
 // subscriptions tracks the live nats.Subscription handles per subject so they
@@ -63,6 +89,45 @@ func (s *subscriptions) add(subject string, sub *nats.Subscription) {
 	s.items[subject] = append(s.items[subject], sub)
 }
 
+// remove removes a single subscription recorded for a subject.
+func (s *subscriptions) remove(subject string, sub *nats.Subscription) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subs := s.items[subject]
+	for i := range subs {
+		if subs[i] == sub {
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) == 0 {
+		delete(s.items, subject)
+	} else {
+		s.items[subject] = subs
+	}
+}
+
+// pending returns the messages and bytes received but not yet handled by the subscriptions.
+func (s *subscriptions) pending() (msgs, bytes int) {
+	if s == nil {
+		return 0, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, subs := range s.items {
+		for _, sub := range subs {
+			if m, b, err := sub.Pending(); err == nil {
+				msgs += m
+				bytes += b
+			}
+		}
+	}
+	return msgs, bytes
+}
+
 // take removes and returns all subscriptions recorded for a subject.
 func (s *subscriptions) take(subject string) []*nats.Subscription {
 	if s == nil {
@@ -77,16 +142,22 @@ func (s *subscriptions) take(subject string) []*nats.Subscription {
 
 // Nats will implement Nats subscribe and publish functionality
 type Nats struct {
-	nc     *nats.Conn
-	wg     *sync.WaitGroup
-	ctx    context.Context
-	cancel context.CancelFunc
-	log    logger.Handler
-	subs   *subscriptions
+	nc      *nats.Conn
+	wg      *sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+	log     logger.Handler
+	subs    *subscriptions
+	monitor *connectionMonitor
+	js      nats.JetStreamContext
+	jsOpts  *JetStreamOptions
+	codecs  *broker.CodecRegistry
+	tracing broker.Tracing
 }
 
 // New - constructor
 func New(opts Options) (broker.Handler, error) {
+	monitor := &connectionMonitor{}
 	nc, err := nats.Connect(strings.Join(opts.URLS, ","),
 		nats.Name(opts.ConnectionName),
 		nats.ReconnectWait(opts.ReconnectWait),
@@ -94,21 +165,27 @@ func New(opts Options) (broker.Handler, error) {
 		nats.RetryOnFailedConnect(opts.RetryOnFailedConnect),
 		nats.UserInfo(opts.Username, opts.Password),
 		nats.Token(opts.Token),
-		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+		nats.ConnectHandler(func(nc *nats.Conn) {
+			monitor.notify(nc, broker.Connected, nil)
+		}),
+		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
+			monitor.notify(nc, broker.Disconnected, err)
 			if opts.Logger != nil {
 				opts.Logger.Error(err)
 			} else {
 				log.Printf("client disconnected: %v", err)
 			}
 		}),
-		nats.ReconnectHandler(func(_ *nats.Conn) {
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			monitor.notify(nc, broker.Reconnected, nil)
 			if opts.Logger != nil {
 				opts.Logger.Info("client reconnected")
 			} else {
 				log.Printf("client reconnected")
 			}
 		}),
-		nats.ClosedHandler(func(_ *nats.Conn) {
+		nats.ClosedHandler(func(nc *nats.Conn) {
+			monitor.notify(nc, broker.Closed, nc.LastError())
 			if opts.Logger != nil {
 				opts.Logger.Info("client closed")
 			} else {
@@ -156,7 +233,14 @@ func New(opts Options) (broker.Handler, error) {
 		}
 	}
 
-	return &Nats{nc: nc, wg: &sync.WaitGroup{}, ctx: ctx, cancel: cancel, log: lg, subs: newSubscriptions()}, nil
+	n := &Nats{nc: nc, wg: &sync.WaitGroup{}, ctx: ctx, cancel: cancel, log: lg, subs: newSubscriptions(), monitor: monitor, codecs: opts.Codecs, tracing: opts.Tracing}
+	if opts.JetStream != nil {
+		if err := n.setupJetStream(opts.JetStream); err != nil {
+			n.CloseConnection()
+			return nil, err
+		}
+	}
+	return n, nil
 }
 
 func (n *Nats) ConnectedEndpoints() (endpoints []string) {
@@ -196,14 +280,18 @@ func (n *Nats) CloseConnection() {
 	}
 }
 
-// Publish - to publish messages (uses JSON encoding)
+// Publish - to publish messages (uses JSON encoding, the Object is encoded with the codec of its ObjectType)
 func (n *Nats) Publish(subject string, message *broker.Message) error {
 	if n == nil || n.nc == nil {
 		return ErrPublish(fmt.Errorf("nats connection is not initialized"))
 	}
 
-	data, err := json.Marshal(message)
+	message, span := n.tracing.StartPublishSpan(messagingSystem, subject, message)
+	defer span.End()
+
+	data, err := n.codecs.Marshal(message)
 	if err != nil {
+		recordSpanError(span, err)
 		if n.log != nil {
 			n.log.Error(err)
 		} else {
@@ -212,7 +300,15 @@ func (n *Nats) Publish(subject string, message *broker.Message) error {
 		return ErrPublish(err)
 	}
 
-	if err := n.nc.Publish(subject, data); err != nil {
+	msg := newMsg(subject, data, message.Headers)
+	if n.streamFor(subject) != "" {
+		// Wait for the stream to acknowledge that the message was persisted.
+		_, err = n.js.PublishMsg(msg)
+	} else {
+		err = n.nc.PublishMsg(msg)
+	}
+	if err != nil {
+		recordSpanError(span, err)
 		if n.log != nil {
 			n.log.Error(err)
 		} else {
@@ -290,14 +386,65 @@ func (n *Nats) Subscribe(subject, queue string, message []byte) error {
 }
 
 // SubscribeWithChannel - for subscribing and forwarding to channel (decodes JSON)
+// In JetStream mode, subjects captured by a stream are consumed through a durable consumer named after the queue.
 func (n *Nats) SubscribeWithChannel(subject, queue string, msgch chan *broker.Message) error {
 	if n == nil || n.nc == nil {
 		return ErrQueueSubscribe(fmt.Errorf("nats connection is not initialized"))
 	}
 
+	sub, err := n.subscribe(subject, queue, func(message *broker.Message) bool {
+		select {
+		case msgch <- message:
+			return true
+		case <-n.ctx.Done():
+			return false
+		}
+	})
+	if err != nil {
+		return err
+	}
+	n.subs.add(subject, sub)
+	return nil
+}
+
+// SubscribeContext - for subscribing with a handler (decodes JSON), until ctx is done or the subscription is unsubscribed
+func (n *Nats) SubscribeContext(ctx context.Context, subject, queue string, handler broker.MessageHandler) (broker.Subscription, error) {
+	if n == nil || n.nc == nil {
+		return nil, ErrQueueSubscribe(fmt.Errorf("nats connection is not initialized"))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, ErrQueueSubscribe(err)
+	}
+
+	sub, err := n.subscribe(subject, queue, func(message *broker.Message) bool {
+		handler(message)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := newSubscription(n, subject, sub)
+	n.subs.add(subject, sub)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = s.Unsubscribe()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// subscribe creates a queue subscription passing the decoded messages to deliver, which reports whether the message
+// was handed over. Subjects captured by a JetStream stream are consumed through JetStream.
+func (n *Nats) subscribe(subject, queue string, deliver func(*broker.Message) bool) (*nats.Subscription, error) {
+	if stream := n.streamFor(subject); stream != "" {
+		return n.subscribeJetStream(stream, subject, queue, deliver)
+	}
+
 	sub, err := n.nc.QueueSubscribe(subject, queue, func(msg *nats.Msg) {
-		var parsed broker.Message
-		if err := json.Unmarshal(msg.Data, &parsed); err != nil {
+		parsed, err := n.codecs.Unmarshal(msg.Data)
+		if err != nil {
 			if n.log != nil {
 				n.log.Error(err)
 			} else {
@@ -305,7 +452,89 @@ func (n *Nats) SubscribeWithChannel(subject, queue string, msgch chan *broker.Me
 			}
 			return
 		}
-		msgch <- &parsed
+		n.deliverTraced(msg, parsed, deliver)
+	})
+	if err != nil {
+		if n.log != nil {
+			n.log.Error(err)
+		} else {
+			log.Printf("queue subscribe error: %v", err)
+		}
+		return nil, ErrQueueSubscribe(err)
+	}
+	return sub, nil
+}
+
+// Request - publishes the message and waits for the first reply, using NATS request/reply (JSON encoding)
+// Subjects captured by a JetStream stream are rejected, as the stream would acknowledge the request before the responders.
+func (n *Nats) Request(ctx context.Context, subject string, message *broker.Message) (*broker.Message, error) {
+	if n == nil || n.nc == nil {
+		return nil, ErrPublishRequest(fmt.Errorf("nats connection is not initialized"))
+	}
+	if stream := n.streamFor(subject); stream != "" {
+		return nil, ErrStreamRequest(subject, stream)
+	}
+
+	message, span := n.tracing.StartPublishSpan(messagingSystem, subject, message.WithContext(ctx))
+	defer span.End()
+
+	data, err := n.codecs.Marshal(message)
+	if err != nil {
+		recordSpanError(span, err)
+		return nil, ErrPublishRequest(err)
+	}
+
+	msg, err := n.nc.RequestMsgWithContext(ctx, newMsg(subject, data, message.Headers))
+	if err != nil {
+		recordSpanError(span, err)
+		return nil, ErrPublishRequest(err)
+	}
+
+	reply, err := n.codecs.Unmarshal(msg.Data)
+	if err != nil {
+		return nil, ErrPublishRequest(err)
+	}
+	if err := broker.ReplyError(reply); err != nil {
+		return reply, ErrPublishRequest(err)
+	}
+	return reply, nil
+}
+
+// HandleRequest - replies to the requests published on the subject with the result of the handler
+// Subjects captured by a JetStream stream are rejected, see Request.
+func (n *Nats) HandleRequest(subject, queue string, handler broker.ReplyHandler) error {
+	if n == nil || n.nc == nil {
+		return ErrQueueSubscribe(fmt.Errorf("nats connection is not initialized"))
+	}
+	if stream := n.streamFor(subject); stream != "" {
+		return ErrStreamRequest(subject, stream)
+	}
+
+	sub, err := n.nc.QueueSubscribe(subject, queue, func(msg *nats.Msg) {
+		var reply *broker.Message
+		if request, err := n.codecs.Unmarshal(msg.Data); err != nil {
+			reply = broker.ErrorReply(err)
+		} else {
+			request.Headers = msgHeaders(msg)
+			request, span := n.tracing.StartConsumeSpan(messagingSystem, msg.Subject, request)
+			reply = broker.Respond(handler, request)
+			if err := broker.ReplyError(reply); err != nil {
+				recordSpanError(span, err)
+			}
+			span.End()
+		}
+
+		data, err := n.codecs.Marshal(reply)
+		if err != nil {
+			data, _ = n.codecs.Marshal(broker.ErrorReply(err))
+		}
+		if err := msg.Respond(data); err != nil {
+			if n.log != nil {
+				n.log.Error(err)
+			} else {
+				log.Printf("failed to reply: %v", err)
+			}
+		}
 	})
 	if err != nil {
 		if n.log != nil {
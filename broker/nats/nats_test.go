@@ -1,6 +1,7 @@
 package nats
 
 import (
+	"context"
 	"testing"
 
 	"github.com/meshery/meshkit/broker"
@@ -50,3 +51,82 @@ func TestNatsUnsubscribeNilConnectionIsNoOp(t *testing.T) {
 		t.Fatalf("uninitialized handler Unsubscribe = %v, want nil", err)
 	}
 }
+
+func TestNatsRequestNilConnection(t *testing.T) {
+	var nilHandler *Nats
+	if _, err := nilHandler.Request(context.Background(), "subj", &broker.Message{}); err == nil {
+		t.Fatal("nil handler Request returned no error")
+	}
+	handler := func(*broker.Message) (*broker.Message, error) { return nil, nil }
+	if err := (&Nats{}).HandleRequest("subj", "queue", handler); err == nil {
+		t.Fatal("uninitialized handler HandleRequest returned no error")
+	}
+}
+
+func TestJetStreamNames(t *testing.T) {
+	if got, want := streamName("meshery.meshsync"), "MESHERY_MESHSYNC"; got != want {
+		t.Fatalf("streamName = %q, want %q", got, want)
+	}
+	if got, want := durableName("meshery-server", "meshery.meshsync.core"), "meshery-server_meshery_meshsync_core"; got != want {
+		t.Fatalf("durableName = %q, want %q", got, want)
+	}
+}
+
+func TestNatsStreamFor(t *testing.T) {
+	n := &Nats{jsOpts: &JetStreamOptions{SubjectPrefixes: []string{"meshery.meshsync"}}}
+	// Without a JetStream context every subject uses core NATS.
+	if got := n.streamFor("meshery.meshsync"); got != "" {
+		t.Fatalf("streamFor without JetStream = %q, want empty", got)
+	}
+
+	js, err := (&nats.Conn{}).JetStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.js = js
+	for subject, want := range map[string]string{
+		"meshery.meshsync":       "MESHERY_MESHSYNC",
+		"meshery.meshsync.core":  "MESHERY_MESHSYNC",
+		"meshery.meshsyncer":     "",
+		"meshery.broadcast.core": "",
+	} {
+		if got := n.streamFor(subject); got != want {
+			t.Errorf("streamFor(%q) = %q, want %q", subject, got, want)
+		}
+	}
+}
+
+func TestNatsSubscribeContextNilConnection(t *testing.T) {
+	var n *Nats
+	if _, err := n.SubscribeContext(context.Background(), "subject", "queue", func(*broker.Message) {}); err == nil {
+		t.Fatal("SubscribeContext on a nil connection must fail")
+	}
+}
+
+func TestSubscriptionsRemove(t *testing.T) {
+	s := newSubscriptions()
+	a, b := &nats.Subscription{}, &nats.Subscription{}
+	s.add("subject", a)
+	s.add("subject", b)
+
+	s.remove("subject", a)
+	if got := s.take("subject"); len(got) != 1 || got[0] != b {
+		t.Fatalf("take(subject) = %v, want only the remaining subscription", got)
+	}
+
+	s.add("subject", a)
+	s.remove("subject", a)
+	if got := s.take("subject"); got != nil {
+		t.Fatalf("take(subject) = %v, want nil once every subscription is removed", got)
+	}
+}
+
+func TestNatsHealthNilConnection(t *testing.T) {
+	var n *Nats
+	if got := n.Health(); got.Connected || got.State != broker.Closed {
+		t.Fatalf("Health() = %+v, want a closed connection", got)
+	}
+	if _, ok := <-n.ConnectionEvents(context.Background()); ok {
+		t.Fatal("ConnectionEvents on a nil connection must be closed")
+	}
+}
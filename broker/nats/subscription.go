@@ -0,0 +1,43 @@
+package nats
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/meshery/meshkit/broker"
+	nats "github.com/nats-io/nats.go"
+)
+
+// subscription is the handle of a subscription created by SubscribeContext.
+type subscription struct {
+	n       *Nats
+	subject string
+	sub     *nats.Subscription
+	done    chan struct{}
+}
+
+var _ broker.Subscription = (*subscription)(nil)
+
+func newSubscription(n *Nats, subject string, sub *nats.Subscription) *subscription {
+	s := &subscription{n: n, subject: subject, sub: sub, done: make(chan struct{})}
+	var once sync.Once
+	// Called once the subscription is closed, including by Unsubscribe(subject) and CloseConnection.
+	sub.SetClosedHandler(func(string) {
+		once.Do(func() { close(s.done) })
+	})
+	return s
+}
+
+// Unsubscribe stops the subscription, leaving the other subscriptions on the subject untouched.
+func (s *subscription) Unsubscribe() error {
+	s.n.subs.remove(s.subject, s.sub)
+	// An already closed subscription reports ErrBadSubscription, ignore it so Unsubscribe stays idempotent.
+	if err := s.sub.Unsubscribe(); err != nil && !errors.Is(err, nats.ErrBadSubscription) {
+		return ErrUnsubscribe(err)
+	}
+	return nil
+}
+
+func (s *subscription) Done() <-chan struct{} {
+	return s.done
+}
@@ -0,0 +1,49 @@
+package nats
+
+import (
+	"github.com/meshery/meshkit/broker"
+	nats "github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// messagingSystem identifies NATS in the attributes of the spans of the messages.
+const messagingSystem = "nats"
+
+// newMsg builds the NATS message of the encoded broker.Message, sending its Headers as NATS message headers.
+func newMsg(subject string, data []byte, headers map[string]string) *nats.Msg {
+	msg := &nats.Msg{Subject: subject, Data: data}
+	// Headers are only set when needed, as they require a server supporting them.
+	if len(headers) > 0 {
+		msg.Header = nats.Header{}
+		for k, v := range headers {
+			msg.Header.Set(k, v)
+		}
+	}
+	return msg
+}
+
+// msgHeaders returns the NATS message headers as the Headers of a broker.Message.
+func msgHeaders(msg *nats.Msg) map[string]string {
+	if len(msg.Header) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(msg.Header))
+	for k := range msg.Header {
+		headers[k] = msg.Header.Get(k)
+	}
+	return headers
+}
+
+// deliverTraced passes the decoded message to deliver within its consumer span, continuing the trace of the publisher.
+func (n *Nats) deliverTraced(msg *nats.Msg, message *broker.Message, deliver func(*broker.Message) bool) bool {
+	message.Headers = msgHeaders(msg)
+	message, span := n.tracing.StartConsumeSpan(messagingSystem, msg.Subject, message)
+	defer span.End()
+	return deliver(message)
+}
+
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,112 @@
+package broker
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/meshery/meshkit/broker"
+
+// Context returns the context of the message. When publishing, it is the context set with WithContext, whose trace
+// context is propagated to the subscribers. When subscribing, it is the context of the consumer span of the message.
+// It defaults to context.Background.
+func (m *Message) Context() context.Context {
+	if m == nil || m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// WithContext returns a shallow copy of the message with its context changed to ctx.
+func (m *Message) WithContext(ctx context.Context) *Message {
+	copied := *m
+	copied.ctx = ctx
+	return &copied
+}
+
+// Tracing sets the tracer provider and the propagator the spans of the messages are started and propagated with.
+// Nil fields default to the ones configured globally with OpenTelemetry, see tracing.InitTracer.
+type Tracing struct {
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
+}
+
+func (t Tracing) tracer() trace.Tracer {
+	if t.TracerProvider == nil {
+		return otel.Tracer(tracerName)
+	}
+	return t.TracerProvider.Tracer(tracerName)
+}
+
+func (t Tracing) propagator() propagation.TextMapPropagator {
+	if t.Propagator == nil {
+		return otel.GetTextMapPropagator()
+	}
+	return t.Propagator
+}
+
+// StartPublishSpan starts the producer span of publishing the message on the subject through the messaging system,
+// with the global tracer provider and propagator, see Tracing.StartPublishSpan.
+func StartPublishSpan(system, subject string, message *Message) (*Message, trace.Span) {
+	return Tracing{}.StartPublishSpan(system, subject, message)
+}
+
+// StartConsumeSpan starts the consumer span of handling the message received on the subject through the messaging
+// system, with the global tracer provider and propagator, see Tracing.StartConsumeSpan.
+func StartConsumeSpan(system, subject string, message *Message) (*Message, trace.Span) {
+	return Tracing{}.StartConsumeSpan(system, subject, message)
+}
+
+// StartPublishSpan starts the producer span of publishing the message on the subject through the messaging system,
+// as a child of the context of the message. The returned message carries the W3C trace context of the span in its
+// Headers, unless there is no trace context to propagate, in which case the message is returned as it is.
+func (t Tracing) StartPublishSpan(system, subject string, message *Message) (*Message, trace.Span) {
+	ctx, span := t.tracer().Start(message.Context(), subject+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(messagingAttributes(system, subject, "publish")...),
+	)
+
+	carrier := propagation.MapCarrier{}
+	t.propagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return message, span
+	}
+
+	traced := message.WithContext(ctx)
+	traced.Headers = make(map[string]string, len(message.Headers)+len(carrier))
+	for k, v := range message.Headers {
+		traced.Headers[k] = v
+	}
+	for k, v := range carrier {
+		traced.Headers[k] = v
+	}
+	return traced, span
+}
+
+// StartConsumeSpan extracts the W3C trace context from the Headers of the message, and starts the consumer span of
+// handling the message received on the subject through the messaging system. The returned message carries the context
+// of the span, see Message.Context, unless there is no trace to continue, in which case it is returned as it is.
+// The caller ends the span once the message is handled.
+func (t Tracing) StartConsumeSpan(system, subject string, message *Message) (*Message, trace.Span) {
+	ctx := t.propagator().Extract(message.Context(), propagation.MapCarrier(message.Headers))
+	ctx, span := t.tracer().Start(ctx, subject+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(messagingAttributes(system, subject, "process")...),
+	)
+	if !span.SpanContext().IsValid() {
+		return message, span
+	}
+	return message.WithContext(ctx), span
+}
+
+func messagingAttributes(system, subject, operation string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("messaging.system", system),
+		attribute.String("messaging.destination.name", subject),
+		attribute.String("messaging.operation", operation),
+	}
+}
@@ -0,0 +1,70 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// newRecorder returns the tracing of a recording tracer provider with the W3C trace context propagator, leaving the
+// global ones untouched.
+func newRecorder() (Tracing, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return Tracing{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		Propagator:     propagation.TraceContext{},
+	}, recorder
+}
+
+func TestMessageTracePropagation(t *testing.T) {
+	tracing, recorder := newRecorder()
+
+	ctx, parent := tracing.TracerProvider.Tracer("test").Start(context.Background(), "operator event")
+	message := &Message{ObjectType: MeshSync, Headers: map[string]string{"key": "value"}}
+	published, span := tracing.StartPublishSpan("nats", "meshery.meshsync", message.WithContext(ctx))
+	span.End()
+	parent.End()
+
+	require.Contains(t, published.Headers, "traceparent")
+	assert.Equal(t, "value", published.Headers["key"])
+	assert.NotContains(t, message.Headers, "traceparent", "the published message must not be modified")
+
+	// The subscriber only gets the headers, as when the message is received from the broker.
+	received, consume := tracing.StartConsumeSpan("nats", "meshery.meshsync", &Message{ObjectType: MeshSync, Headers: published.Headers})
+	consume.End()
+	assert.Equal(t, trace.SpanContextFromContext(received.Context()), consume.SpanContext())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	producer, consumer := spans[0], spans[2]
+	assert.Equal(t, "meshery.meshsync publish", producer.Name())
+	assert.Equal(t, trace.SpanKindProducer, producer.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), producer.Parent().SpanID())
+	assert.Equal(t, "meshery.meshsync process", consumer.Name())
+	assert.Equal(t, trace.SpanKindConsumer, consumer.SpanKind())
+	assert.Equal(t, producer.SpanContext().TraceID(), consumer.SpanContext().TraceID())
+	assert.Equal(t, producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+	assert.True(t, consumer.Parent().IsRemote())
+}
+
+func TestMessageTracePropagation_Disabled(t *testing.T) {
+	// Without tracing configured, messages are passed through unchanged.
+	tracing := Tracing{TracerProvider: noop.NewTracerProvider(), Propagator: propagation.NewCompositeTextMapPropagator()}
+
+	message := &Message{ObjectType: MeshSync}
+	published, span := tracing.StartPublishSpan("channel", "meshery.meshsync", message)
+	span.End()
+	assert.Same(t, message, published)
+
+	received, span := tracing.StartConsumeSpan("channel", "meshery.meshsync", message)
+	span.End()
+	assert.Same(t, message, received)
+	assert.Equal(t, context.Background(), received.Context())
+}
@@ -15,6 +15,8 @@
 // Package config provides the interface Handler and errors related to the configuration of adapters.
 package config
 
+import "context"
+
 // Interface Handler is the interface to be implemented by config providers used by adapters.
 //
 // Provided implementations can be found in the package config/provider.
@@ -32,3 +34,25 @@ type Handler interface {
 
 	SetObject(key string, value interface{}) error
 }
+
+// Interface KeyLookup is implemented by the config providers telling whether a key is set, rather than returning
+// the empty value for a key which is not set.
+type KeyLookup interface {
+	// LookupKey retrieves the string value of the given key, and whether it is set.
+	LookupKey(key string) (string, bool)
+}
+
+// Type ConfigChange is a change of the value of a key, notified to the watchers of the key.
+// An empty value means that the key is not set.
+type ConfigChange struct {
+	Key      string
+	OldValue string
+	NewValue string
+}
+
+// Interface Watcher is implemented by the config providers notifying the changes of their keys.
+type Watcher interface {
+	// Watch returns a channel receiving the changes of the given key and of the keys nested under it, e.g. of
+	// log.level for the key log. An empty key watches every key. The channel is closed once ctx is done.
+	Watch(ctx context.Context, key string) <-chan ConfigChange
+}
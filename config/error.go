@@ -22,6 +22,10 @@ var (
 	ErrEmptyConfigCode = "meshkit-11123"
 	ErrViperCode       = "meshkit-11124"
 	ErrInMemCode       = "meshkit-11125"
+	ErrLayeredCode     = "meshkit-11337"
+	ErrEnvCode         = "meshkit-11338"
+	ErrFlagsCode       = "meshkit-11339"
+	ErrSecretCode      = "meshkit-11348"
 
 	// ErrEmptyConfig is returned when the config has not been initialized.
 	ErrEmptyConfig = errors.New(ErrEmptyConfigCode, errors.Alert, []string{"Config not initialized"}, []string{}, []string{"Viper is crashing"}, []string{"Make sure viper is configured properly"})
@@ -36,3 +40,24 @@ func ErrViper(err error) error {
 func ErrInMem(err error) error {
 	return errors.New(ErrInMemCode, errors.Fatal, []string{"InMem configuration initialization failed"}, []string{err.Error()}, []string{"In memory map is crashing"}, []string{"Make sure map is configured properly"})
 }
+
+// ErrLayered returns a MeshKit error indicating an error in the layered provider.
+func ErrLayered(err error) error {
+	return errors.New(ErrLayeredCode, errors.Alert, []string{"Layered configuration failed"}, []string{err.Error()}, []string{"The layers are misconfigured", "The key is not set in any layer"}, []string{"Make sure every layer has a unique name and a provider", "Make sure the key is set in at least one layer, e.g. in the defaults"})
+}
+
+// ErrEnv returns a MeshKit error indicating an error in the environment provider.
+func ErrEnv(err error) error {
+	return errors.New(ErrEnvCode, errors.Alert, []string{"Environment configuration failed"}, []string{err.Error()}, []string{"The value of the environment variable is not valid JSON or YAML"}, []string{"Make sure the environment variable holds a JSON or YAML value"})
+}
+
+// ErrFlags returns a MeshKit error indicating an error in the command-line flags provider.
+func ErrFlags(err error) error {
+	return errors.New(ErrFlagsCode, errors.Alert, []string{"Command-line flags configuration failed"}, []string{err.Error()}, []string{"The flag set is not provided", "The flag is not defined", "The value of the flag is not valid"}, []string{"Make sure the flag set is passed in the provider options and defines the flag", "Make sure the value of the flag has the expected type"})
+}
+
+// ErrSecret returns a MeshKit error indicating that an object with resolved secrets cannot be decoded.
+// It does not include the values of the secrets.
+func ErrSecret(err error) error {
+	return errors.New(ErrSecretCode, errors.Alert, []string{"Secret configuration failed"}, []string{err.Error()}, []string{"The object with its resolved secrets does not match the expected type"}, []string{"Make sure the secrets have the type of the values they replace"})
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"os"
+	"strings"
+
+	"github.com/meshery/meshkit/config"
+	"github.com/meshery/meshkit/encoding"
+	"github.com/meshery/meshkit/utils"
+)
+
+var envReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// Type Env implements the config interface Handler for the environment variables of the process.
+//
+// A key maps to the upper-cased environment variable with the prefix from the Options, and the dots and dashes of
+// the key replaced by underscores: with the prefix MESHERY, the key broker.url maps to MESHERY_BROKER_URL.
+type Env struct {
+	prefix string
+}
+
+// NewEnv returns a new instance of an environment configuration provider using the provided Options opts.
+func NewEnv(opts Options) (config.Handler, error) {
+	return &Env{
+		prefix: opts.EnvPrefix,
+	}, nil
+}
+
+// Name returns the name of the environment variable of the key.
+func (e *Env) Name(key string) string {
+	name := strings.ToUpper(envReplacer.Replace(key))
+	if e.prefix == "" {
+		return name
+	}
+	return strings.ToUpper(e.prefix) + "_" + name
+}
+
+// SetKey sets the environment variable of the key
+func (e *Env) SetKey(key string, value string) {
+	_ = os.Setenv(e.Name(key), value)
+}
+
+// GetKey gets the environment variable of the key
+func (e *Env) GetKey(key string) string {
+	return os.Getenv(e.Name(key))
+}
+
+// LookupKey gets the environment variable of the key, and whether it is set
+func (e *Env) LookupKey(key string) (string, bool) {
+	return os.LookupEnv(e.Name(key))
+}
+
+// GetObject gets an object value for the key, from the JSON or YAML value of its environment variable
+func (e *Env) GetObject(key string, result interface{}) error {
+	if err := encoding.Unmarshal([]byte(e.GetKey(key)), result); err != nil {
+		return config.ErrEnv(err)
+	}
+	return nil
+}
+
+// SetObject sets the environment variable of the key to the JSON value of the object
+func (e *Env) SetObject(key string, value interface{}) error {
+	val, err := utils.Marshal(value)
+	if err != nil {
+		return config.ErrEnv(err)
+	}
+	if err := os.Setenv(e.Name(key), val); err != nil {
+		return config.ErrEnv(err)
+	}
+	return nil
+}
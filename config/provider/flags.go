@@ -0,0 +1,126 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/meshery/meshkit/config"
+	"github.com/meshery/meshkit/encoding"
+	"github.com/meshery/meshkit/utils"
+	"github.com/spf13/pflag"
+)
+
+// Type Flags implements the config interface Handler for a set of command-line flags.
+//
+// A key maps to the flag of the same name or, failing that, to the flag named after the key with its dots replaced
+// by dashes: the key broker.url maps to the flag --broker-url. Only the flags set on the command line are reported
+// as set by LookupKey, so that the default values of the flags do not take precedence over the other layers of a
+// Layered provider.
+type Flags struct {
+	flags *pflag.FlagSet
+}
+
+// NewFlags returns a new instance of a command-line flags configuration provider using the flag set of the provided
+// Options opts.
+func NewFlags(opts Options) (config.Handler, error) {
+	if opts.Flags == nil {
+		return nil, config.ErrFlags(fmt.Errorf("no flag set provided"))
+	}
+	return &Flags{
+		flags: opts.Flags,
+	}, nil
+}
+
+func (f *Flags) lookup(key string) *pflag.Flag {
+	if flag := f.flags.Lookup(key); flag != nil {
+		return flag
+	}
+	return f.flags.Lookup(strings.ReplaceAll(key, ".", "-"))
+}
+
+// SetKey sets the value of the flag of the key, as if set on the command line
+func (f *Flags) SetKey(key string, value string) {
+	if flag := f.lookup(key); flag != nil {
+		_ = f.flags.Set(flag.Name, value)
+	}
+}
+
+// GetKey gets the value of the flag of the key, which is its default value unless set on the command line
+func (f *Flags) GetKey(key string) string {
+	if flag := f.lookup(key); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
+}
+
+// LookupKey gets the value of the flag of the key, and whether it was set on the command line
+func (f *Flags) LookupKey(key string) (string, bool) {
+	flag := f.lookup(key)
+	if flag == nil || !flag.Changed {
+		return "", false
+	}
+	return flag.Value.String(), true
+}
+
+// GetObject gets an object value for the key, from the JSON or YAML value of its flag.
+// The values of the slice flags, such as --endpoints=a,b, are retrieved as lists.
+func (f *Flags) GetObject(key string, result interface{}) error {
+	flag := f.lookup(key)
+	if flag == nil {
+		return config.ErrFlags(fmt.Errorf("flag %s is not defined", key))
+	}
+	value := []byte(flag.Value.String())
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		var err error
+		if value, err = json.Marshal(slice.GetSlice()); err != nil {
+			return config.ErrFlags(err)
+		}
+	}
+	if err := encoding.Unmarshal(value, result); err != nil {
+		return config.ErrFlags(err)
+	}
+	return nil
+}
+
+// SetObject sets the flag of the key to the JSON value of the object.
+// The values of the slice flags are replaced by the items of the list.
+func (f *Flags) SetObject(key string, value interface{}) error {
+	flag := f.lookup(key)
+	if flag == nil {
+		return config.ErrFlags(fmt.Errorf("flag %s is not defined", key))
+	}
+	val, err := utils.Marshal(value)
+	if err != nil {
+		return config.ErrFlags(err)
+	}
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		var items []string
+		if err := json.Unmarshal([]byte(val), &items); err != nil {
+			return config.ErrFlags(err)
+		}
+		if err := slice.Replace(items); err != nil {
+			return config.ErrFlags(err)
+		}
+		flag.Changed = true
+		return nil
+	}
+	if err := f.flags.Set(flag.Name, val); err != nil {
+		return config.ErrFlags(err)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@
 package provider
 
 import (
+	"context"
 	"sync"
 
 	"github.com/meshery/meshkit/config"
@@ -24,8 +25,9 @@ import (
 
 // Type InMem implements the config interface Handler for an in-memory configuration registry.
 type InMem struct {
-	store map[string]string
-	mutex sync.Mutex
+	store    map[string]string
+	mutex    sync.Mutex
+	watchers watchers
 }
 
 // NewInMem returns a new instance of an in-memory configuration provider using the provided Options opts.
@@ -40,7 +42,7 @@ func NewInMem(opts Options) (config.Handler, error) {
 // SetKey sets a key value in local store
 func (l *InMem) SetKey(key string, value string) {
 	l.mutex.Lock()
-	l.store[key] = value
+	l.set(key, value)
 	l.mutex.Unlock()
 }
 
@@ -51,6 +53,14 @@ func (l *InMem) GetKey(key string) string {
 	return l.store[key]
 }
 
+// LookupKey gets a key value from local store, and whether it is set
+func (l *InMem) LookupKey(key string) (string, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	value, ok := l.store[key]
+	return value, ok
+}
+
 // GetObject gets an object value for the key
 func (l *InMem) GetObject(key string, result interface{}) error {
 	l.mutex.Lock()
@@ -66,6 +76,21 @@ func (l *InMem) SetObject(key string, value interface{}) error {
 	if err != nil {
 		return config.ErrInMem(err)
 	}
-	l.store[key] = val
+	l.set(key, val)
 	return nil
 }
+
+// Watch returns a channel receiving the changes of the key, see config.Watcher
+func (l *InMem) Watch(ctx context.Context, key string) <-chan config.ConfigChange {
+	return l.watchers.add(ctx, key)
+}
+
+// set sets a key value in local store, and notifies the watchers of the key of its change.
+// The watchers are notified with the lock held, so that they receive the changes in order.
+func (l *InMem) set(key, value string) {
+	old := l.store[key]
+	l.store[key] = value
+	if old != value {
+		l.watchers.notify(config.ConfigChange{Key: key, OldValue: old, NewValue: value})
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/meshery/meshkit/config"
+	"github.com/meshery/meshkit/encoding"
+	"github.com/meshery/meshkit/utils"
+)
+
+const (
+	// Names of the layers commonly used, in order of precedence
+	DefaultsLayer  = "defaults"
+	FileLayer      = "file"
+	EnvLayer       = "env"
+	FlagsLayer     = "flags"
+	OverridesLayer = "overrides"
+)
+
+// Type Layer is a config provider used as a layer of a Layered provider, under a name telling where its values come from.
+type Layer struct {
+	Name    string
+	Handler config.Handler
+}
+
+// Type Layered implements the config interface Handler over several layers of config providers, in increasing order
+// of precedence: a key resolves to its value in the last layer where it is set.
+//
+// A key is set in a layer if the provider of the layer implements config.KeyLookup and reports the key as set, or
+// else if the provider returns a non-empty value for the key. Values are set in the last layer, usually the runtime
+// overrides.
+type Layered struct {
+	layers []Layer
+}
+
+// NewLayered returns a new instance of a layered configuration provider, using the provided layers in increasing
+// order of precedence, e.g.
+//
+//	NewLayered(
+//		Layer{Name: DefaultsLayer, Handler: defaults},
+//		Layer{Name: FileLayer, Handler: file},
+//		Layer{Name: EnvLayer, Handler: env},
+//		Layer{Name: FlagsLayer, Handler: flags},
+//		Layer{Name: OverridesLayer, Handler: overrides},
+//	)
+func NewLayered(layers ...Layer) (*Layered, error) {
+	if len(layers) == 0 {
+		return nil, config.ErrLayered(fmt.Errorf("no layers provided"))
+	}
+	names := make(map[string]bool, len(layers))
+	for _, layer := range layers {
+		if layer.Handler == nil {
+			return nil, config.ErrLayered(fmt.Errorf("layer %q has no config provider", layer.Name))
+		}
+		if names[layer.Name] {
+			return nil, config.ErrLayered(fmt.Errorf("layer %q is provided more than once", layer.Name))
+		}
+		names[layer.Name] = true
+	}
+	return &Layered{
+		layers: append([]Layer(nil), layers...),
+	}, nil
+}
+
+// SetKey sets a key value in the last layer
+func (l *Layered) SetKey(key string, value string) {
+	l.layers[len(l.layers)-1].Handler.SetKey(key, value)
+}
+
+// GetKey gets a key value from the last layer where the key is set
+func (l *Layered) GetKey(key string) string {
+	value, _ := l.LookupKey(key)
+	return value
+}
+
+// LookupKey gets a key value from the last layer where the key is set, and whether it is set in any layer
+func (l *Layered) LookupKey(key string) (string, bool) {
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		if value, ok := lookupKey(l.layers[i].Handler, key); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// GetObject gets an object value for the key, merging the objects of the layers where the key is set in increasing
+// order of precedence: the fields set by a layer override the ones of the lower layers, and so do the sub-keys of the
+// object set in a layer, e.g. log.level set in the env layer overrides the level of the log object of the file layer.
+// Sub-keys are only looked up for the fields of the objects of the lower layers.
+func (l *Layered) GetObject(key string, result interface{}) error {
+	var merged interface{}
+	var sources []Layer
+	for _, layer := range l.layers {
+		changed := false
+		if _, ok := lookupKey(layer.Handler, key); ok {
+			var object interface{}
+			if err := layer.Handler.GetObject(key, &object); err != nil {
+				return err
+			}
+			merged, changed = mergeObjects(merged, normalizeObject(object)), true
+		}
+		if merged != nil {
+			var overridden bool
+			merged, overridden = overrideSubKeys(layer.Handler, key, merged)
+			changed = changed || overridden
+		}
+		if changed {
+			sources = append(sources, layer)
+		}
+	}
+	switch len(sources) {
+	case 0:
+		return config.ErrLayered(fmt.Errorf("key %s is not set in any layer", key))
+	case 1:
+		// Nothing to merge, the object is decoded by its provider as it would without layers.
+		return sources[0].Handler.GetObject(key, result)
+	}
+	data, err := utils.Marshal(merged)
+	if err != nil {
+		return config.ErrLayered(err)
+	}
+	if err := encoding.Unmarshal([]byte(data), result); err != nil {
+		return config.ErrLayered(err)
+	}
+	return nil
+}
+
+// SetObject sets an object value for the key in the last layer
+func (l *Layered) SetObject(key string, value interface{}) error {
+	return l.layers[len(l.layers)-1].Handler.SetObject(key, value)
+}
+
+// Source returns the name of the layer supplying the value of the key, and whether the key is set in any layer.
+func (l *Layered) Source(key string) (string, bool) {
+	layer, ok := l.source(key)
+	return layer.Name, ok
+}
+
+// Layer returns the config provider of the named layer, e.g. to set the defaults.
+func (l *Layered) Layer(name string) (config.Handler, bool) {
+	for _, layer := range l.layers {
+		if layer.Name == name {
+			return layer.Handler, true
+		}
+	}
+	return nil, false
+}
+
+// Watch returns a channel receiving the changes of the resolved values of the key, see config.Watcher.
+//
+// It watches the layers whose providers implement config.Watcher, and notifies the changes which are not shadowed by
+// a higher layer. The channel is closed once ctx is done, or once the channels of all the watched layers are closed.
+func (l *Layered) Watch(ctx context.Context, key string) <-chan config.ConfigChange {
+	changes := make(chan config.ConfigChange, watchBufferSize)
+	var wg sync.WaitGroup
+	for i, layer := range l.layers {
+		watcher, ok := layer.Handler.(config.Watcher)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, layerChanges <-chan config.ConfigChange) {
+			defer wg.Done()
+			for change := range layerChanges {
+				resolved, ok := l.resolve(i, change)
+				if !ok {
+					continue
+				}
+				select {
+				case changes <- resolved:
+				default:
+				}
+			}
+		}(i, watcher.Watch(ctx, key))
+	}
+	go func() {
+		wg.Wait()
+		close(changes)
+	}()
+	return changes
+}
+
+// resolve returns the change of the resolved value of the key of the change of the i-th layer, unless the change is
+// shadowed by a higher layer or does not change the resolved value.
+func (l *Layered) resolve(i int, change config.ConfigChange) (config.ConfigChange, bool) {
+	for _, layer := range l.layers[i+1:] {
+		if _, ok := lookupKey(layer.Handler, change.Key); ok {
+			return change, false
+		}
+	}
+	// The key resolves to its value in the lower layers when not set in the i-th layer.
+	if change.OldValue == "" {
+		change.OldValue = l.lookupBelow(i, change.Key)
+	}
+	if change.NewValue == "" {
+		change.NewValue = l.lookupBelow(i, change.Key)
+	}
+	return change, change.OldValue != change.NewValue
+}
+
+func (l *Layered) lookupBelow(i int, key string) string {
+	for j := i - 1; j >= 0; j-- {
+		if value, ok := lookupKey(l.layers[j].Handler, key); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+func (l *Layered) source(key string) (Layer, bool) {
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		if _, ok := lookupKey(l.layers[i].Handler, key); ok {
+			return l.layers[i], true
+		}
+	}
+	return Layer{}, false
+}
+
+// mergeObjects returns the fields of the maps of value merged over the ones of base. Other values replace base.
+func mergeObjects(base, value interface{}) interface{} {
+	baseMap, ok := base.(map[string]interface{})
+	valueMap, isMap := value.(map[string]interface{})
+	if !ok || !isMap {
+		return value
+	}
+	merged := make(map[string]interface{}, len(baseMap)+len(valueMap))
+	for k, v := range baseMap {
+		merged[k] = v
+	}
+	for k, v := range valueMap {
+		merged[k] = mergeObjects(merged[k], v)
+	}
+	return merged
+}
+
+// overrideSubKeys returns the object of the key with the values of its fields replaced by the ones of their sub-keys
+// set in handler, and whether any value changed. The values keep the type of the fields they replace.
+func overrideSubKeys(handler config.Handler, key string, object interface{}) (interface{}, bool) {
+	fields, ok := object.(map[string]interface{})
+	if !ok {
+		value, ok := lookupKey(handler, key)
+		if !ok || fmt.Sprint(object) == value {
+			return object, false
+		}
+		if _, isString := object.(string); isString {
+			return value, true
+		}
+		var parsed interface{}
+		if err := encoding.Unmarshal([]byte(value), &parsed); err != nil {
+			return value, true
+		}
+		return parsed, true
+	}
+	changed := false
+	overridden := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		value, ok := overrideSubKeys(handler, key+"."+field, value)
+		overridden[field] = value
+		changed = changed || ok
+	}
+	if !changed {
+		return object, false
+	}
+	return overridden, true
+}
+
+// normalizeObject returns the value with the maps keyed by interface{}, as decoded from YAML, keyed by strings.
+func normalizeObject(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			normalized[fmt.Sprint(k)] = normalizeObject(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			normalized[k] = normalizeObject(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeObject(item)
+		}
+		return normalized
+	default:
+		return value
+	}
+}
+
+func lookupKey(handler config.Handler, key string) (string, bool) {
+	if lookup, ok := handler.(config.KeyLookup); ok {
+		return lookup.LookupKey(key)
+	}
+	value := handler.GetKey(key)
+	return value, value != ""
+}
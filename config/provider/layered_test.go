@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func newTestLayered(t *testing.T, args ...string) *Layered {
+	t.Helper()
+	defaults, _ := NewInMem(Options{})
+	defaults.SetKey("log.level", "info")
+	defaults.SetKey("broker.url", "nats://localhost:4222")
+	defaults.SetKey("port", "10000")
+	env, _ := NewEnv(Options{EnvPrefix: "meshkit_test"})
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("log-level", "warn", "")
+	fs.StringSlice("endpoints", nil, "")
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	flags, err := NewFlags(Options{Flags: fs})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overrides, _ := NewInMem(Options{})
+
+	l, err := NewLayered(
+		Layer{Name: DefaultsLayer, Handler: defaults},
+		Layer{Name: EnvLayer, Handler: env},
+		Layer{Name: FlagsLayer, Handler: flags},
+		Layer{Name: OverridesLayer, Handler: overrides},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return l
+}
+
+func TestLayered_Precedence(t *testing.T) {
+	t.Setenv("MESHKIT_TEST_BROKER_URL", "nats://broker:4222")
+	t.Setenv("MESHKIT_TEST_LOG_LEVEL", "debug")
+	l := newTestLayered(t, "--log-level=error")
+
+	tests := []struct {
+		key    string
+		value  string
+		source string
+	}{
+		{"port", "10000", DefaultsLayer},
+		{"broker.url", "nats://broker:4222", EnvLayer},
+		{"log.level", "error", FlagsLayer},
+	}
+	for _, tt := range tests {
+		if got := l.GetKey(tt.key); got != tt.value {
+			t.Errorf("GetKey(%s): expected %s, got %s", tt.key, tt.value, got)
+		}
+		if got, ok := l.Source(tt.key); !ok || got != tt.source {
+			t.Errorf("Source(%s): expected %s, got %s", tt.key, tt.source, got)
+		}
+	}
+
+	l.SetKey("broker.url", "nats://override:4222")
+	if got := l.GetKey("broker.url"); got != "nats://override:4222" {
+		t.Errorf("expected the override, got %s", got)
+	}
+	if got, _ := l.Source("broker.url"); got != OverridesLayer {
+		t.Errorf("expected %s, got %s", OverridesLayer, got)
+	}
+}
+
+func TestLayered_FlagDefaults(t *testing.T) {
+	// The default value of a flag not set on the command line does not override the lower layers.
+	l := newTestLayered(t)
+	if got := l.GetKey("log.level"); got != "info" {
+		t.Errorf("expected info, got %s", got)
+	}
+}
+
+func TestLayered_Missing(t *testing.T) {
+	l := newTestLayered(t)
+	if got, ok := l.LookupKey("missing"); ok || got != "" {
+		t.Errorf("expected missing key to be unset, got %s", got)
+	}
+	if _, ok := l.Source("missing"); ok {
+		t.Error("expected no source for missing key")
+	}
+	var result map[string]interface{}
+	if err := l.GetObject("missing", &result); err == nil {
+		t.Error("expected an error for missing key")
+	}
+}
+
+func TestLayered_GetObject(t *testing.T) {
+	t.Setenv("MESHKIT_TEST_ADAPTER", `{"name": "istio", "port": 10000}`)
+	l := newTestLayered(t, "--endpoints=a:1,b:2")
+
+	var adapter struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+	if err := l.GetObject("adapter", &adapter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adapter.Name != "istio" || adapter.Port != 10000 {
+		t.Errorf("unexpected object %+v", adapter)
+	}
+
+	var endpoints []string
+	if err := l.GetObject("endpoints", &endpoints); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(endpoints) != 2 || endpoints[0] != "a:1" || endpoints[1] != "b:2" {
+		t.Errorf("unexpected endpoints %v", endpoints)
+	}
+
+	if err := l.SetObject("adapter", map[string]string{"name": "linkerd"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := l.GetObject("adapter", &adapter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adapter.Name != "linkerd" {
+		t.Errorf("expected the override, got %s", adapter.Name)
+	}
+}
+
+func TestLayered_GetObjectSubKeys(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("log:\n  level: info\n  format: json\n  caller: true\n  sampling:\n    initial: 100\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	file, err := NewViper(Options{FilePath: dir, FileName: "config", FileType: "yaml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env, _ := NewEnv(Options{EnvPrefix: "meshkit"})
+	overrides, _ := NewInMem(Options{})
+	l, err := NewLayered(
+		Layer{Name: FileLayer, Handler: file},
+		Layer{Name: EnvLayer, Handler: env},
+		Layer{Name: OverridesLayer, Handler: overrides},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv("MESHKIT_LOG_LEVEL", "debug")
+	t.Setenv("MESHKIT_LOG_SAMPLING_INITIAL", "10")
+
+	type logConfig struct {
+		Level    string `json:"level"`
+		Format   string `json:"format"`
+		Caller   bool   `json:"caller"`
+		Sampling struct {
+			Initial int `json:"initial"`
+		} `json:"sampling"`
+	}
+	var log logConfig
+	if err := l.GetObject("log", &log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := l.GetKey("log.level"); log.Level != got {
+		t.Errorf("expected the level of GetKey, %s, got %s", got, log.Level)
+	}
+	if log.Level != "debug" || log.Format != "json" || !log.Caller || log.Sampling.Initial != 10 {
+		t.Errorf("unexpected object %+v", log)
+	}
+
+	// Objects of higher layers are merged over the lower ones.
+	if err := l.SetObject("log", map[string]string{"format": "terminal"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	log = logConfig{}
+	if err := l.GetObject("log", &log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Level != "debug" || log.Format != "terminal" || !log.Caller {
+		t.Errorf("unexpected object %+v", log)
+	}
+}
+
+func TestNewLayered_Invalid(t *testing.T) {
+	h, _ := NewInMem(Options{})
+	if _, err := NewLayered(); err == nil {
+		t.Error("expected an error without layers")
+	}
+	if _, err := NewLayered(Layer{Name: DefaultsLayer}); err == nil {
+		t.Error("expected an error for a layer without provider")
+	}
+	if _, err := NewLayered(Layer{Name: DefaultsLayer, Handler: h}, Layer{Name: DefaultsLayer, Handler: h}); err == nil {
+		t.Error("expected an error for duplicate layers")
+	}
+}
+
+func TestNewFlags_NoFlagSet(t *testing.T) {
+	if _, err := NewFlags(Options{}); err == nil {
+		t.Error("expected an error without flag set")
+	}
+}
@@ -17,10 +17,20 @@
 // Package provider provides config provider implementations that can be used in the adapters, as well as the Options type containing options for various aspects of an adapter.
 package provider
 
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
 const (
 	// Provider keys
-	ViperKey = "viper"
-	InMemKey = "in-mem"
+	ViperKey   = "viper"
+	InMemKey   = "in-mem"
+	EnvKey     = "env"
+	FlagsKey   = "flags"
+	LayeredKey = "layered"
+	SecretKey  = "secret"
 )
 
 // Type Options contains config options for various aspects of an adapter.
@@ -28,4 +38,12 @@ type Options struct {
 	FilePath string
 	FileType string
 	FileName string
+	// WatchDebounce is the delay after the last change of the config file before the Viper provider reloads it,
+	// DefaultWatchDebounce by default.
+	WatchDebounce time.Duration
+
+	// EnvPrefix is the prefix of the environment variables read by the Env provider, e.g. MESHERY for MESHERY_LOG_LEVEL.
+	EnvPrefix string
+	// Flags is the flag set read by the Flags provider.
+	Flags *pflag.FlagSet
 }
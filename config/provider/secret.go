@@ -0,0 +1,231 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/meshery/meshkit/config"
+	"github.com/meshery/meshkit/config/secret"
+	"github.com/meshery/meshkit/errors"
+	"github.com/meshery/meshkit/logger"
+)
+
+// Type Secret implements the config interface Handler over another config provider, resolving the secret references
+// of its values, e.g. secret://env/BROKER_PASSWORD, see the package config/secret.
+//
+// The secrets are resolved when read and never stored in the underlying provider: setting a key which refers to a
+// secret stores the value in the backend of the secret rather than in the provider, e.g. in the config file of Viper.
+type Secret struct {
+	handler  config.Handler
+	resolver *secret.Resolver
+	log      logger.Handler
+}
+
+// NewSecret returns a new instance of a config provider resolving the secret references of the values of handler
+// through resolver. The values SetKey cannot store are reported to log, or to the standard logger if log is nil.
+func NewSecret(handler config.Handler, resolver *secret.Resolver, log logger.Handler) *Secret {
+	return &Secret{
+		handler:  handler,
+		resolver: resolver,
+		log:      log,
+	}
+}
+
+// SetKey sets a key value, in the backend of the secret if the key refers to a secret.
+// The value is dropped, and the error logged without the value, if the backend of the secret cannot store it,
+// see SetSecretKey.
+func (s *Secret) SetKey(key string, value string) {
+	if err := s.SetSecretKey(key, value); err != nil {
+		s.logError("set", key, err)
+	}
+}
+
+// logError logs the error of an operation on the key, without the value of the key.
+func (s *Secret) logError(operation, key string, err error) {
+	if s.log != nil {
+		s.log.Error(err)
+		return
+	}
+	log.Printf("failed to %s config key %s: %s %s", operation, key, errors.GetCode(err), errors.GetSDescription(err))
+}
+
+// SetSecretKey sets a key value, in the backend of the secret if the key refers to a secret, and returns an error if
+// the backend cannot store it. Setting a secret reference re-points the key to the referred secret.
+func (s *Secret) SetSecretKey(key string, value string) error {
+	if secret.IsReference(value) {
+		s.handler.SetKey(key, value)
+		return nil
+	}
+	if reference, ok := lookupKey(s.handler, key); ok && secret.IsReference(reference) {
+		return s.resolver.Set(reference, value)
+	}
+	s.handler.SetKey(key, value)
+	return nil
+}
+
+// GetKey gets a key value, resolving the secret it refers to. Secrets which cannot be resolved read as empty values,
+// see LookupKey.
+func (s *Secret) GetKey(key string) string {
+	value, _ := s.LookupKey(key)
+	return value
+}
+
+// LookupKey gets a key value resolving the secret it refers to, and whether it is set.
+// A secret which cannot be resolved reads as an empty value and the error is logged, see LookupSecretKey.
+func (s *Secret) LookupKey(key string) (string, bool) {
+	value, ok, err := s.LookupSecretKey(key)
+	if err != nil {
+		s.logError("resolve", key, err)
+	}
+	return value, ok
+}
+
+// LookupSecretKey gets a key value resolving the secret it refers to, and whether it is set, and returns an error if
+// the secret cannot be resolved, e.g. to check the secrets of the configuration at startup.
+func (s *Secret) LookupSecretKey(key string) (string, bool, error) {
+	value, ok := lookupKey(s.handler, key)
+	if !ok {
+		return "", false, nil
+	}
+	resolved, err := s.resolver.Resolve(value)
+	if err != nil {
+		return "", true, err
+	}
+	return resolved, true, nil
+}
+
+// GetObject gets an object value for the key, resolving the secrets referred to by its strings
+func (s *Secret) GetObject(key string, result interface{}) error {
+	var object interface{}
+	if err := s.handler.GetObject(key, &object); err != nil {
+		return err
+	}
+	resolved, err := s.resolver.ResolveAll(object)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		return config.ErrSecret(err)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return config.ErrSecret(err)
+	}
+	return nil
+}
+
+// SetObject sets an object value for the key in the underlying provider, keeping the secret references it holds:
+// the values of the fields referring to a secret are stored in the backend of the secret if they changed, as with
+// SetSecretKey, so that an object read with GetObject can be written back without storing its secrets in the provider.
+func (s *Secret) SetObject(key string, value interface{}) error {
+	var stored interface{}
+	if err := s.handler.GetObject(key, &stored); err != nil || !hasReference(stored) {
+		return s.handler.SetObject(key, value)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return config.ErrSecret(err)
+	}
+	var object interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return config.ErrSecret(err)
+	}
+	object, err = s.keepReferences(stored, object)
+	if err != nil {
+		return err
+	}
+	return s.handler.SetObject(key, object)
+}
+
+// keepReferences returns the value with the secret references of stored, the object previously set, put back, storing
+// the changed secrets in their backends.
+func (s *Secret) keepReferences(stored, value interface{}) (interface{}, error) {
+	switch stored := stored.(type) {
+	case string:
+		v, ok := value.(string)
+		if !ok || !secret.IsReference(stored) || secret.IsReference(v) {
+			return value, nil
+		}
+		if resolved, err := s.resolver.Resolve(stored); err == nil && resolved == v {
+			return stored, nil
+		}
+		if err := s.resolver.Set(stored, v); err != nil {
+			return nil, err
+		}
+		return stored, nil
+	case map[string]interface{}:
+		v, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		for key, item := range v {
+			for storedKey, storedItem := range stored {
+				// Keys are matched case-insensitively, as providers such as Viper lowercase them.
+				if !strings.EqualFold(key, storedKey) {
+					continue
+				}
+				kept, err := s.keepReferences(storedItem, item)
+				if err != nil {
+					return nil, err
+				}
+				v[key] = kept
+				break
+			}
+		}
+		return v, nil
+	case []interface{}:
+		v, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		for i := range v {
+			if i >= len(stored) {
+				break
+			}
+			kept, err := s.keepReferences(stored[i], v[i])
+			if err != nil {
+				return nil, err
+			}
+			v[i] = kept
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// hasReference tells whether a string of the value, including the ones nested in maps and slices, is a secret reference.
+func hasReference(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return secret.IsReference(v)
+	case map[string]interface{}:
+		for _, item := range v {
+			if hasReference(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasReference(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
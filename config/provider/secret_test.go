@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/meshery/meshkit/config/secret"
+	"github.com/meshery/meshkit/errors"
+)
+
+func TestSecret(t *testing.T) {
+	t.Setenv("MESHKIT_TEST_PASSWORD", "s3cr3t")
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := secret.GenerateKeyFile(keyFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store, err := secret.NewEncryptedFile(filepath.Join(dir, "secrets.enc"), keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resolver := secret.NewResolver()
+	resolver.Register("env", secret.NewEnv("MESHKIT_TEST_"))
+	resolver.Register("store", store)
+
+	inner, _ := NewInMem(Options{})
+	inner.SetKey("broker.password", "secret://env/PASSWORD")
+	inner.SetKey("broker.token", "secret://store/token")
+	if err := inner.SetObject("broker", map[string]interface{}{"url": "nats://localhost:4222", "password": "secret://env/PASSWORD"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := NewSecret(inner, resolver, nil)
+
+	if got := s.GetKey("broker.password"); got != "s3cr3t" {
+		t.Errorf("expected s3cr3t, got %s", got)
+	}
+	var broker struct {
+		URL      string `json:"url"`
+		Password string `json:"password"`
+	}
+	if err := s.GetObject("broker", &broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if broker.Password != "s3cr3t" || broker.URL != "nats://localhost:4222" {
+		t.Errorf("unexpected object %+v", broker)
+	}
+
+	// Secrets are set in their backend, the underlying provider keeps the references.
+	s.SetKey("broker.token", "t0k3n")
+	if got := inner.GetKey("broker.token"); got != "secret://store/token" {
+		t.Errorf("expected the secret reference to be kept, got %s", got)
+	}
+	if got := s.GetKey("broker.token"); got != "t0k3n" {
+		t.Errorf("expected t0k3n, got %s", got)
+	}
+	if err := s.SetSecretKey("broker.password", "other"); err == nil {
+		t.Error("expected an error setting a secret in a read-only backend")
+	}
+	if got := os.Getenv("MESHKIT_TEST_PASSWORD"); got != "s3cr3t" {
+		t.Errorf("expected the environment to be unchanged, got %s", got)
+	}
+	if got := inner.GetKey("broker.password"); got != "secret://env/PASSWORD" {
+		t.Errorf("expected the secret reference to be kept, got %s", got)
+	}
+
+	// Setting a reference re-points the key instead of storing the reference as the secret.
+	s.SetKey("broker.token", "secret://env/PASSWORD")
+	if got := inner.GetKey("broker.token"); got != "secret://env/PASSWORD" {
+		t.Errorf("expected the key to refer to the new secret, got %s", got)
+	}
+	if got, _ := store.Get("token"); got != "t0k3n" {
+		t.Errorf("expected the previous secret to be unchanged, got %s", got)
+	}
+
+	s.SetKey("log.level", "debug")
+	if got := inner.GetKey("log.level"); got != "debug" {
+		t.Errorf("expected debug, got %s", got)
+	}
+
+	// Secrets which cannot be resolved read as empty values, and are reported by LookupSecretKey.
+	inner.SetKey("broker.username", "secret://env/USERNAME")
+	if got, ok := s.LookupKey("broker.username"); got != "" || !ok {
+		t.Errorf("expected an empty value which is set, got %q, %v", got, ok)
+	}
+	if _, ok, err := s.LookupSecretKey("broker.username"); !ok || errors.GetCode(err) != secret.ErrSecretNotFoundCode {
+		t.Errorf("expected a %s error, got %v", secret.ErrSecretNotFoundCode, err)
+	}
+	if got, ok, err := s.LookupSecretKey("broker.password"); got != "s3cr3t" || !ok || err != nil {
+		t.Errorf("expected s3cr3t, got %q, %v, %v", got, ok, err)
+	}
+}
+
+func TestSecret_SetObject(t *testing.T) {
+	t.Setenv("MESHKIT_TEST_PASSWORD", "s3cr3t")
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := secret.GenerateKeyFile(keyFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store, err := secret.NewEncryptedFile(filepath.Join(dir, "secrets.enc"), keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Set("token", "t0k3n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resolver := secret.NewResolver()
+	resolver.Register("env", secret.NewEnv("MESHKIT_TEST_"))
+	resolver.Register("store", store)
+
+	inner, _ := NewInMem(Options{})
+	if err := inner.SetObject("broker", map[string]interface{}{
+		"url":      "nats://localhost:4222",
+		"password": "secret://env/PASSWORD",
+		"token":    "secret://store/token",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := NewSecret(inner, resolver, nil)
+
+	type brokerConfig struct {
+		URL      string `json:"url"`
+		Password string `json:"password"`
+		Token    string `json:"token"`
+	}
+	stored := func() map[string]interface{} {
+		var object map[string]interface{}
+		if err := inner.GetObject("broker", &object); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return object
+	}
+
+	// A read-modify-write keeps the references of the unchanged secrets, and stores the changed ones in their backend.
+	var broker brokerConfig
+	if err := s.GetObject("broker", &broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	broker.URL = "nats://broker:4222"
+	broker.Token = "n3w"
+	if err := s.SetObject("broker", broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	object := stored()
+	if object["url"] != "nats://broker:4222" || object["password"] != "secret://env/PASSWORD" || object["token"] != "secret://store/token" {
+		t.Errorf("expected the secret references to be kept, got %v", object)
+	}
+	if got, _ := store.Get("token"); got != "n3w" {
+		t.Errorf("expected n3w, got %s", got)
+	}
+
+	// A secret of a read-only backend cannot be changed.
+	broker.Password = "other"
+	if err := s.SetObject("broker", broker); err == nil {
+		t.Error("expected an error setting a secret in a read-only backend")
+	}
+	if object := stored(); object["password"] != "secret://env/PASSWORD" || object["url"] != "nats://broker:4222" {
+		t.Errorf("expected the object to be unchanged, got %v", object)
+	}
+
+	// Setting a reference re-points the field.
+	broker.Password = "secret://store/password"
+	if err := s.SetObject("broker", broker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if object := stored(); object["password"] != "secret://store/password" {
+		t.Errorf("expected the field to refer to the new secret, got %v", object)
+	}
+}
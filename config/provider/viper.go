@@ -15,9 +15,15 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/meshery/meshkit/config"
 	"github.com/spf13/viper"
 )
@@ -26,12 +32,24 @@ const (
 	FilePath = "filepath"
 	FileType = "filetype"
 	FileName = "filename"
+
+	// DefaultWatchDebounce is the delay after the last change of the config file before it is reloaded.
+	DefaultWatchDebounce = 100 * time.Millisecond
 )
 
 // Type Viper implements the config interface Handler for a Viper configuration registry.
 type Viper struct {
 	instance *viper.Viper
 	mutex    sync.Mutex
+
+	file     string
+	debounce time.Duration
+	watchers watchers
+	// values are the values of the keys as of the last reload, once the config file is watched
+	values    map[string]string
+	watchOnce sync.Once
+	watcher   *fsnotify.Watcher
+	watchErr  error
 }
 
 // NewViper returns a new instance of a Viper configuration provider using the provided Options opts.
@@ -57,8 +75,14 @@ func NewViper(opts Options) (config.Handler, error) {
 		}
 	}
 
+	debounce := opts.WatchDebounce
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
 	return &Viper{
 		instance: v,
+		file:     filepath.Join(opts.FilePath, fmt.Sprintf("%s.%s", opts.FileName, opts.FileType)),
+		debounce: debounce,
 	}, nil
 }
 
@@ -76,6 +100,16 @@ func (v *Viper) GetKey(key string) string {
 	return v.instance.Get(key).(string)
 }
 
+func (v *Viper) LookupKey(key string) (string, bool) {
+	v.mutex.Lock()
+	_ = v.instance.ReadInConfig()
+	defer v.mutex.Unlock()
+	if !v.instance.IsSet(key) {
+		return "", false
+	}
+	return v.instance.GetString(key), true
+}
+
 func (v *Viper) GetObject(key string, result interface{}) error {
 	v.mutex.Lock()
 	_ = v.instance.ReadInConfig()
@@ -98,3 +132,141 @@ func (v *Viper) SetObject(key string, value interface{}) error {
 
 	return nil
 }
+
+// Watch returns a channel receiving the changes of the key, see config.Watcher.
+//
+// The first call starts watching the config file: once the file has not changed for the debounce delay of the
+// Options, it is reloaded and the watchers of the keys whose values changed are notified. The channel is closed once
+// ctx is done, by Close, or right away if the config file cannot be watched.
+func (v *Viper) Watch(ctx context.Context, key string) <-chan config.ConfigChange {
+	v.watchOnce.Do(func() {
+		v.watchErr = v.startWatching()
+	})
+	if v.watchErr != nil {
+		changes := make(chan config.ConfigChange)
+		close(changes)
+		return changes
+	}
+	return v.watchers.add(ctx, key)
+}
+
+// Close stops watching the config file, and closes the channels returned by Watch.
+func (v *Viper) Close() error {
+	v.watchOnce.Do(func() {
+		v.watchErr = fmt.Errorf("config provider closed")
+	})
+	v.watchers.close()
+	if v.watcher == nil {
+		return nil
+	}
+	if err := v.watcher.Close(); err != nil {
+		return config.ErrViper(err)
+	}
+	return nil
+}
+
+func (v *Viper) startWatching() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// The directory is watched rather than the file, so that the file is still watched after being replaced, as by
+	// editors and by Kubernetes when updating a mounted ConfigMap.
+	if err := watcher.Add(filepath.Dir(v.file)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	v.mutex.Lock()
+	_ = v.instance.ReadInConfig()
+	v.values = v.snapshot()
+	v.mutex.Unlock()
+
+	v.watcher = watcher
+	go v.watch(watcher)
+	return nil
+}
+
+// watch reloads the config file once it has not changed for the debounce delay, until the watcher is closed.
+func (v *Viper) watch(watcher *fsnotify.Watcher) {
+	var (
+		timer  *time.Timer
+		reload <-chan time.Time
+	)
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !v.isConfigFileEvent(event) {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(v.debounce)
+			} else {
+				timer.Reset(v.debounce)
+			}
+			reload = timer.C
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-reload:
+			reload = nil
+			v.reload()
+		}
+	}
+}
+
+func (v *Viper) isConfigFileEvent(event fsnotify.Event) bool {
+	// ..data is the symbolic link swapped by Kubernetes to update the files of a mounted ConfigMap or Secret
+	return filepath.Clean(event.Name) == filepath.Clean(v.file) || filepath.Base(event.Name) == "..data"
+}
+
+// reload reads the config file and notifies the watchers of the keys whose values changed.
+func (v *Viper) reload() {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if err := v.instance.ReadInConfig(); err != nil {
+		// e.g. the file is being written, it is reloaded again once written
+		return
+	}
+	old := v.values
+	v.values = v.snapshot()
+
+	keys := make([]string, 0, len(old)+len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+	for key := range old {
+		if _, ok := v.values[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if old[key] != v.values[key] {
+			v.watchers.notify(config.ConfigChange{Key: key, OldValue: old[key], NewValue: v.values[key]})
+		}
+	}
+}
+
+// snapshot returns the values of all the keys, as strings.
+func (v *Viper) snapshot() map[string]string {
+	values := make(map[string]string)
+	for _, key := range v.instance.AllKeys() {
+		switch value := v.instance.Get(key).(type) {
+		case nil:
+		case string:
+			values[key] = value
+		default:
+			if data, err := json.Marshal(value); err == nil {
+				values[key] = string(data)
+			} else {
+				values[key] = fmt.Sprint(value)
+			}
+		}
+	}
+	return values
+}
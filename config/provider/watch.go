@@ -0,0 +1,105 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/meshery/meshkit/config"
+)
+
+// watchBufferSize is the number of changes buffered for a watcher, beyond which changes are dropped rather than
+// blocking the provider.
+const watchBufferSize = 16
+
+type watch struct {
+	key     string
+	changes chan config.ConfigChange
+	// stop stops waiting for the context of the watch to be done.
+	stop func() bool
+}
+
+// watchers are the watchers of the keys of a config provider.
+type watchers struct {
+	mutex   sync.Mutex
+	watches []watch
+	closed  bool
+}
+
+// add returns the channel of a new watcher of the key, which is closed once ctx is done or the watchers are closed.
+func (w *watchers) add(ctx context.Context, key string) <-chan config.ConfigChange {
+	changes := make(chan config.ConfigChange, watchBufferSize)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed || ctx.Err() != nil {
+		close(changes)
+		return changes
+	}
+	stop := context.AfterFunc(ctx, func() {
+		w.remove(changes)
+	})
+	w.watches = append(w.watches, watch{key: key, changes: changes, stop: stop})
+	return changes
+}
+
+// remove removes the watcher of the channel and closes its channel.
+func (w *watchers) remove(changes chan config.ConfigChange) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for i, watch := range w.watches {
+		if watch.changes == changes {
+			w.watches = append(w.watches[:i], w.watches[i+1:]...)
+			close(changes)
+			return
+		}
+	}
+}
+
+// notify sends the change to the watchers of its key, without waiting for the watchers falling behind.
+func (w *watchers) notify(change config.ConfigChange) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, watch := range w.watches {
+		if !watches(watch.key, change.Key) {
+			continue
+		}
+		select {
+		case watch.changes <- change:
+		default:
+		}
+	}
+}
+
+// close closes the channels of the watchers.
+func (w *watchers) close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	for _, watch := range w.watches {
+		watch.stop()
+		close(watch.changes)
+	}
+	w.watches = nil
+}
+
+// watches tells whether the watcher of the watched key is notified of the changes of the key.
+func watches(watched, key string) bool {
+	return watched == "" || key == watched || strings.HasPrefix(key, watched+".")
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/meshery/meshkit/config"
+)
+
+func receiveChange(t *testing.T, changes <-chan config.ConfigChange) config.ConfigChange {
+	t.Helper()
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a config change")
+	}
+	return config.ConfigChange{}
+}
+
+func assertNoChange(t *testing.T, changes <-chan config.ConfigChange) {
+	t.Helper()
+	select {
+	case change := <-changes:
+		t.Fatalf("unexpected config change %+v", change)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInMem_Watch(t *testing.T) {
+	h, _ := NewInMem(Options{})
+	changes := h.(config.Watcher).Watch(context.Background(), "log")
+	all := h.(config.Watcher).Watch(context.Background(), "")
+
+	h.SetKey("log.level", "debug")
+	h.SetKey("logger", "json")
+	if got := receiveChange(t, changes); got != (config.ConfigChange{Key: "log.level", NewValue: "debug"}) {
+		t.Errorf("unexpected change %+v", got)
+	}
+	assertNoChange(t, changes)
+	if got := receiveChange(t, all); got.Key != "log.level" {
+		t.Errorf("unexpected change %+v", got)
+	}
+	if got := receiveChange(t, all); got.Key != "logger" {
+		t.Errorf("unexpected change %+v", got)
+	}
+
+	// Setting the same value is not a change.
+	h.SetKey("log.level", "debug")
+	assertNoChange(t, changes)
+	h.SetKey("log.level", "info")
+	if got := receiveChange(t, changes); got != (config.ConfigChange{Key: "log.level", OldValue: "debug", NewValue: "info"}) {
+		t.Errorf("unexpected change %+v", got)
+	}
+}
+
+func TestViper_Watch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("log:\n  level: info\nport: 10000\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h, err := NewViper(Options{FilePath: dir, FileName: "config", FileType: "yaml", WatchDebounce: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v := h.(*Viper)
+	changes := v.Watch(context.Background(), "log")
+
+	// Several writes in a row are reloaded once.
+	for _, level := range []string{"warn", "error", "debug"} {
+		if err := os.WriteFile(file, []byte("log:\n  level: "+level+"\nport: 10001\n"), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := receiveChange(t, changes); got != (config.ConfigChange{Key: "log.level", OldValue: "info", NewValue: "debug"}) {
+		t.Errorf("unexpected change %+v", got)
+	}
+	assertNoChange(t, changes)
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := <-changes; ok {
+		t.Error("expected the channel to be closed")
+	}
+	if _, ok := <-v.Watch(context.Background(), "log"); ok {
+		t.Error("expected the channel to be closed")
+	}
+}
+
+func TestLayered_Watch(t *testing.T) {
+	defaults, _ := NewInMem(Options{})
+	defaults.SetKey("log.level", "info")
+	file, _ := NewInMem(Options{})
+	overrides, _ := NewInMem(Options{})
+	l, err := NewLayered(
+		Layer{Name: DefaultsLayer, Handler: defaults},
+		Layer{Name: FileLayer, Handler: file},
+		Layer{Name: OverridesLayer, Handler: overrides},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := l.Watch(ctx, "log.level")
+
+	file.SetKey("log.level", "debug")
+	if got := receiveChange(t, changes); got != (config.ConfigChange{Key: "log.level", OldValue: "info", NewValue: "debug"}) {
+		t.Errorf("unexpected change %+v", got)
+	}
+
+	// Changes shadowed by a higher layer are not notified.
+	overrides.SetKey("log.level", "error")
+	if got := receiveChange(t, changes); got != (config.ConfigChange{Key: "log.level", OldValue: "debug", NewValue: "error"}) {
+		t.Errorf("unexpected change %+v", got)
+	}
+	file.SetKey("log.level", "warn")
+	assertNoChange(t, changes)
+
+	// Cancelling the watch stops watching the layers.
+	cancel()
+	for range changes {
+	}
+	for _, layer := range []*InMem{defaults.(*InMem), file.(*InMem), overrides.(*InMem)} {
+		layer.watchers.mutex.Lock()
+		watching := len(layer.watchers.watches)
+		layer.watchers.mutex.Unlock()
+		if watching != 0 {
+			t.Errorf("expected no watcher left, got %d", watching)
+		}
+	}
+}
+
+func TestInMem_WatchCancel(t *testing.T) {
+	h, _ := NewInMem(Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := h.(config.Watcher).Watch(ctx, "log")
+	other := h.(config.Watcher).Watch(context.Background(), "log")
+
+	cancel()
+	if _, ok := <-changes; ok {
+		t.Error("expected the channel to be closed")
+	}
+	h.SetKey("log.level", "debug")
+	if got := receiveChange(t, other); got.Key != "log.level" {
+		t.Errorf("unexpected change %+v", got)
+	}
+	if _, ok := <-h.(config.Watcher).Watch(ctx, "log"); ok {
+		t.Error("expected the channel of a done context to be closed")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Env is a backend reading the secrets from the environment variables of the process.
+type Env struct {
+	prefix string
+}
+
+// NewEnv returns a backend reading the secret of a name from the environment variable prefix + name.
+func NewEnv(prefix string) *Env {
+	return &Env{prefix: prefix}
+}
+
+// Get returns the value of the environment variable of the secret.
+func (e *Env) Get(name string) (string, error) {
+	value, ok := os.LookupEnv(e.prefix + name)
+	if !ok {
+		return "", ErrSecretNotFound(name)
+	}
+	return value, nil
+}
+
+// Dir is a backend reading the secrets from the files of a directory, e.g. a Kubernetes Secret mounted as a volume.
+type Dir struct {
+	dir string
+}
+
+// NewDir returns a backend reading the secret of a name from the file of that name in dir.
+func NewDir(dir string) *Dir {
+	return &Dir{dir: dir}
+}
+
+// Get returns the content of the file of the secret, without its trailing newlines.
+func (d *Dir) Get(name string) (string, error) {
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", ErrInvalidReference(Scheme+"<dir>/"+name, fmt.Errorf("the name of a secret cannot be a path"))
+	}
+	data, err := os.ReadFile(filepath.Join(d.dir, name))
+	if os.IsNotExist(err) {
+		return "", ErrSecretNotFound(name)
+	}
+	if err != nil {
+		return "", ErrSecretStore(err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
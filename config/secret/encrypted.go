@@ -0,0 +1,161 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// keySize is the size of the AES-256 keys of the encrypted files.
+const keySize = 32
+
+// EncryptedFile is a backend storing the secrets in a local file, encrypted with AES-256-GCM using the key of a key
+// file.
+type EncryptedFile struct {
+	path  string
+	aead  cipher.AEAD
+	mutex sync.Mutex
+}
+
+// GenerateKeyFile writes a new random key to keyFile, which must not exist, readable by its owner only.
+func GenerateKeyFile(keyFile string) error {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return ErrSecretStore(err)
+	}
+	file, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return ErrSecretStore(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		return ErrSecretStore(err)
+	}
+	return nil
+}
+
+// NewEncryptedFile returns a backend storing the secrets in the file at path, encrypted with the base64 encoded key
+// of keyFile, see GenerateKeyFile. The file is created by the first Set.
+func NewEncryptedFile(path, keyFile string) (*EncryptedFile, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, ErrSecretStore(err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, ErrSecretStore(fmt.Errorf("invalid key file %s: %w", keyFile, err))
+	}
+	if len(key) != keySize {
+		return nil, ErrSecretStore(fmt.Errorf("invalid key file %s: expected a %d bytes key", keyFile, keySize))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrSecretStore(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, ErrSecretStore(err)
+	}
+	return &EncryptedFile{path: path, aead: aead}, nil
+}
+
+// Get returns the secret of the given name.
+func (e *EncryptedFile) Get(name string) (string, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	secrets, err := e.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", ErrSecretNotFound(name)
+	}
+	return value, nil
+}
+
+// Set stores the secret of the given name.
+func (e *EncryptedFile) Set(name, value string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	secrets, err := e.load()
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return e.save(secrets)
+}
+
+func (e *EncryptedFile) load() (map[string]string, error) {
+	data, err := os.ReadFile(e.path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, ErrSecretStore(err)
+	}
+	nonceSize := e.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, ErrSecretStore(fmt.Errorf("%s is not an encrypted secrets file", e.path))
+	}
+	plaintext, err := e.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrSecretStore(fmt.Errorf("unable to decrypt %s, the key may be wrong: %w", e.path, err))
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, ErrSecretStore(err)
+	}
+	return secrets, nil
+}
+
+// save encrypts the secrets with a new nonce, and replaces the file atomically.
+func (e *EncryptedFile) save(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return ErrSecretStore(err)
+	}
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return ErrSecretStore(err)
+	}
+	data := e.aead.Seal(nonce, nonce, plaintext, nil)
+
+	file, err := os.CreateTemp(filepath.Dir(e.path), filepath.Base(e.path)+".*")
+	if err != nil {
+		return ErrSecretStore(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return ErrSecretStore(err)
+	}
+	if err := file.Close(); err != nil {
+		return ErrSecretStore(err)
+	}
+	if err := os.Rename(file.Name(), e.path); err != nil {
+		return ErrSecretStore(err)
+	}
+	return nil
+}
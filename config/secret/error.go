@@ -0,0 +1,55 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+
+	"github.com/meshery/meshkit/errors"
+)
+
+// The errors never include the values of the secrets, only their references.
+var (
+	ErrInvalidReferenceCode = "meshkit-11343"
+	ErrUnknownBackendCode   = "meshkit-11344"
+	ErrSecretNotFoundCode   = "meshkit-11345"
+	ErrSecretStoreCode      = "meshkit-11346"
+	ErrReadOnlySecretCode   = "meshkit-11347"
+)
+
+// ErrInvalidReference returns a MeshKit error indicating that the secret reference is malformed.
+func ErrInvalidReference(reference string, err error) error {
+	return errors.New(ErrInvalidReferenceCode, errors.Alert, []string{fmt.Sprintf("Invalid secret reference %s", reference)}, []string{err.Error()}, []string{"The secret reference is malformed"}, []string{fmt.Sprintf("Use secret references of the form %s<backend>/<name>", Scheme)})
+}
+
+// ErrUnknownBackend returns a MeshKit error indicating that no secret backend is registered under the name.
+func ErrUnknownBackend(name string) error {
+	return errors.New(ErrUnknownBackendCode, errors.Alert, []string{fmt.Sprintf("Unknown secret backend %s", name)}, []string{fmt.Sprintf("No secret backend is registered under the name %s", name)}, []string{"The secret backend is not registered", "The secret reference is misspelled"}, []string{"Register the secret backend with the resolver", "Check the backend of the secret reference"})
+}
+
+// ErrSecretNotFound returns a MeshKit error indicating that the secret is not found in its backend.
+func ErrSecretNotFound(name string) error {
+	return errors.New(ErrSecretNotFoundCode, errors.Alert, []string{fmt.Sprintf("Secret %s not found", name)}, []string{fmt.Sprintf("The secret %s is not found in its backend", name)}, []string{"The secret is not provisioned", "The secret reference is misspelled"}, []string{"Provision the secret, e.g. set the environment variable or mount the Kubernetes Secret", "Check the name of the secret reference"})
+}
+
+// ErrSecretStore returns a MeshKit error indicating that a secret backend cannot be read or written.
+func ErrSecretStore(err error) error {
+	return errors.New(ErrSecretStoreCode, errors.Alert, []string{"Secret store failed"}, []string{err.Error()}, []string{"The secrets file or directory is not accessible", "The key file is invalid or does not match the secrets file"}, []string{"Check the permissions of the secrets files", "Make sure the key file is the one the secrets file was encrypted with"})
+}
+
+// ErrReadOnlySecret returns a MeshKit error indicating that the backend of the secret reference cannot store secrets.
+func ErrReadOnlySecret(reference string) error {
+	return errors.New(ErrReadOnlySecretCode, errors.Alert, []string{fmt.Sprintf("Secret %s is read-only", reference)}, []string{"The backend of the secret reference cannot store secrets"}, []string{"The value of a config key referring to a secret in a read-only backend was set"}, []string{"Update the secret in its backend, e.g. the environment or the Kubernetes Secret, instead of the configuration"})
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secret resolves the secret references found in the configuration of adapters.
+//
+// A secret reference is a config value of the form secret://<backend>/<name>, e.g. secret://env/BROKER_PASSWORD,
+// which resolves to the secret of the given name in the backend registered under the given name. The configuration
+// only holds the references, so that the secrets are never written to the config files.
+package secret
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Scheme is the prefix of the secret references.
+const Scheme = "secret://"
+
+// Backend is a store of secrets.
+type Backend interface {
+	// Get returns the secret of the given name, or ErrSecretNotFound.
+	Get(name string) (string, error)
+}
+
+// Setter is implemented by the backends storing secrets.
+type Setter interface {
+	// Set stores the secret of the given name.
+	Set(name, value string) error
+}
+
+// Resolver resolves the secret references through the backends registered under their names.
+type Resolver struct {
+	mutex    sync.RWMutex
+	backends map[string]Backend
+}
+
+// NewResolver returns a resolver without backends.
+func NewResolver() *Resolver {
+	return &Resolver{
+		backends: make(map[string]Backend),
+	}
+}
+
+// Register registers the backend under name, e.g. env for the references secret://env/<name>.
+func (r *Resolver) Register(name string, backend Backend) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.backends[name] = backend
+}
+
+// IsReference tells whether the value is a secret reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// Parse returns the backend and the name of the secret of the reference.
+func Parse(reference string) (backend string, name string, err error) {
+	if !IsReference(reference) {
+		return "", "", ErrInvalidReference(reference, fmt.Errorf("missing %s scheme", Scheme))
+	}
+	backend, name, _ = strings.Cut(strings.TrimPrefix(reference, Scheme), "/")
+	if backend == "" || name == "" {
+		return "", "", ErrInvalidReference(reference, fmt.Errorf("expected %s<backend>/<name>", Scheme))
+	}
+	return backend, name, nil
+}
+
+// Resolve returns the secret the value refers to, or the value itself if it is not a secret reference.
+func (r *Resolver) Resolve(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	backend, name, err := r.backend(value)
+	if err != nil {
+		return "", err
+	}
+	return backend.Get(name)
+}
+
+// ResolveAll returns a copy of the value with the secret references among its strings, including the ones nested in
+// maps and slices, resolved.
+func (r *Resolver) ResolveAll(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return r.Resolve(v)
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			item, err := r.ResolveAll(item)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = item
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			item, err := r.ResolveAll(item)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = item
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+// Set stores the secret the reference refers to, in a backend implementing Setter.
+func (r *Resolver) Set(reference, value string) error {
+	backend, name, err := r.backend(reference)
+	if err != nil {
+		return err
+	}
+	setter, ok := backend.(Setter)
+	if !ok {
+		return ErrReadOnlySecret(reference)
+	}
+	return setter.Set(name, value)
+}
+
+func (r *Resolver) backend(reference string) (Backend, string, error) {
+	name, secret, err := Parse(reference)
+	if err != nil {
+		return nil, "", err
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	backend, ok := r.backends[name]
+	if !ok {
+		return nil, "", ErrUnknownBackend(name)
+	}
+	return backend, secret, nil
+}
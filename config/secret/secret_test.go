@@ -0,0 +1,127 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		reference string
+		backend   string
+		name      string
+		valid     bool
+	}{
+		{"secret://env/BROKER_PASSWORD", "env", "BROKER_PASSWORD", true},
+		{"secret://file/nested/name", "file", "nested/name", true},
+		{"secret://env/", "", "", false},
+		{"secret://env", "", "", false},
+		{"env/BROKER_PASSWORD", "", "", false},
+	}
+	for _, tt := range tests {
+		backend, name, err := Parse(tt.reference)
+		if (err == nil) != tt.valid || backend != tt.backend || name != tt.name {
+			t.Errorf("Parse(%s): unexpected %s, %s, %v", tt.reference, backend, name, err)
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	t.Setenv("MESHKIT_TEST_PASSWORD", "s3cr3t")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("t0k3n\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := NewResolver()
+	r.Register("env", NewEnv("MESHKIT_TEST_"))
+	r.Register("file", NewDir(dir))
+
+	for value, expected := range map[string]string{
+		"plain":                 "plain",
+		"secret://env/PASSWORD": "s3cr3t",
+		"secret://file/token":   "t0k3n",
+	} {
+		got, err := r.Resolve(value)
+		if err != nil || got != expected {
+			t.Errorf("Resolve(%s): expected %s, got %s, %v", value, expected, got, err)
+		}
+	}
+	for _, value := range []string{"secret://env/MISSING", "secret://file/missing", "secret://file/..", "secret://vault/password"} {
+		if _, err := r.Resolve(value); err == nil {
+			t.Errorf("Resolve(%s): expected an error", value)
+		}
+	}
+
+	resolved, err := r.ResolveAll(map[string]interface{}{
+		"broker": map[string]interface{}{"password": "secret://env/PASSWORD", "port": 4222.0},
+		"tokens": []interface{}{"secret://file/token", "plain"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"broker": map[string]interface{}{"password": "s3cr3t", "port": 4222.0},
+		"tokens": []interface{}{"t0k3n", "plain"},
+	}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("expected %v, got %v", expected, resolved)
+	}
+
+	if err := r.Set("secret://env/PASSWORD", "other"); err == nil {
+		t.Error("expected an error setting a secret in a read-only backend")
+	}
+}
+
+func TestEncryptedFile(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	path := filepath.Join(dir, "secrets.enc")
+	if err := GenerateKeyFile(keyFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := GenerateKeyFile(keyFile); err == nil {
+		t.Error("expected an error overwriting a key file")
+	}
+
+	store, err := NewEncryptedFile(path, keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.Get("password"); err == nil {
+		t.Error("expected an error for a missing secret")
+	}
+	if err := store.Set("password", "s3cr3t"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "s3cr3t") || strings.Contains(string(data), "password") {
+		t.Error("expected the secrets file to be encrypted")
+	}
+
+	reopened, err := NewEncryptedFile(path, keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := reopened.Get("password"); err != nil || got != "s3cr3t" {
+		t.Errorf("expected s3cr3t, got %s, %v", got, err)
+	}
+
+	otherKey := filepath.Join(dir, "other")
+	if err := GenerateKeyFile(otherKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wrong, err := NewEncryptedFile(path, otherKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := wrong.Get("password"); err == nil {
+		t.Error("expected an error decrypting with the wrong key")
+	}
+}
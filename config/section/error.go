@@ -0,0 +1,58 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package section
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/meshery/meshkit/errors"
+)
+
+var (
+	ErrRegisterSectionCode = "meshkit-11340"
+	ErrValidateConfigCode  = "meshkit-11341"
+	ErrDecodeSectionCode   = "meshkit-11342"
+)
+
+// ErrRegisterSection returns a MeshKit error indicating that the section under key cannot be registered.
+func ErrRegisterSection(key string, err error) error {
+	return errors.New(ErrRegisterSectionCode, errors.Alert, []string{fmt.Sprintf("Unable to register config section %s", key)}, []string{err.Error()}, []string{"The section is already registered", "The type of the section is not a struct", "The JSON Schema of the section is invalid"}, []string{"Register every section once, with a struct type or a valid JSON Schema"})
+}
+
+// ErrValidateConfig returns a MeshKit error reporting the problems found in the configuration.
+// The problems are available as its additional info, see ProblemsFromError.
+func ErrValidateConfig(problems []Problem) error {
+	descriptions := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		descriptions = append(descriptions, problem.String())
+	}
+	return errors.NewV2(ErrValidateConfigCode, errors.Alert, []string{"Invalid configuration"}, descriptions, []string{"The configuration has unknown keys, e.g. misspelled ones", "The configuration misses required keys", "The configuration has values of the wrong type"}, []string{"Fix the reported keys of the configuration"}, problems)
+}
+
+// ErrDecodeSection returns a MeshKit error indicating that the section under key cannot be read.
+func ErrDecodeSection(key string, err error) error {
+	return errors.New(ErrDecodeSectionCode, errors.Alert, []string{fmt.Sprintf("Unable to read config section %s", key)}, []string{err.Error()}, []string{"The section is not an object", "The type of the section is not a struct"}, []string{"Make sure the section is an object matching its type"})
+}
+
+// ProblemsFromError returns the problems reported by an error returned by Validate or Get.
+func ProblemsFromError(err error) ([]Problem, bool) {
+	var meshkitError *errors.ErrorV2
+	if !stderrors.As(err, &meshkitError) {
+		return nil, false
+	}
+	problems, ok := meshkitError.AdditionalInfo.([]Problem)
+	return problems, ok
+}
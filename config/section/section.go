@@ -0,0 +1,362 @@
+// Copyright 2021 Meshery Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package section provides typed and validated sections of the configuration of adapters.
+//
+// A section is the object under a key of a config.Handler, described either by a Go struct or by a JSON Schema.
+// The fields of a Go struct are named by their json tags, and support two more tags:
+//
+//	type LogConfig struct {
+//		Level  string `json:"level" default:"info"`
+//		Format string `json:"format" required:"true"`
+//	}
+//
+// A field missing from the section is set to the value of its default tag, or reported as a problem if it is
+// required. A missing nested struct is checked as an empty object, so its required fields are reported as well.
+// Keys which do not match any field, e.g. typos, are reported as problems too.
+package section
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"github.com/meshery/meshkit/config"
+	"github.com/meshery/meshkit/utils"
+)
+
+// Problem is a problem found in the configuration, at the given key.
+type Problem struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+type section struct {
+	key string
+	// typ is the Go struct of the section, unless it is described by schema
+	typ    reflect.Type
+	schema cue.Value
+}
+
+// Registry holds the sections of the configuration of an adapter, so that they are validated all at once on start.
+type Registry struct {
+	mutex    sync.RWMutex
+	sections []section
+}
+
+// NewRegistry returns an empty registry of config sections.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers the Go struct T as the type of the section under key.
+func Register[T any](r *Registry, key string) error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return ErrRegisterSection(key, fmt.Errorf("%s is not a struct", typ))
+	}
+	return r.register(section{key: key, typ: typ})
+}
+
+// RegisterSchema registers the JSON Schema of the section under key.
+func (r *Registry) RegisterSchema(key string, jsonSchema string) error {
+	schema, err := utils.JsonSchemaToCue(jsonSchema)
+	if err != nil {
+		return ErrRegisterSection(key, err)
+	}
+	return r.register(section{key: key, schema: schema})
+}
+
+func (r *Registry) register(s section) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, registered := range r.sections {
+		if registered.key == s.key {
+			return ErrRegisterSection(s.key, fmt.Errorf("section is already registered"))
+		}
+	}
+	r.sections = append(r.sections, s)
+	return nil
+}
+
+// Validate validates every registered section of the configuration provided by handler, and reports all the problems
+// found at once, see ProblemsFromError.
+func (r *Registry) Validate(handler config.Handler) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	problems := []Problem{}
+	for _, s := range r.sections {
+		raw, err := read(handler, s.key)
+		if err != nil {
+			problems = append(problems, Problem{Key: s.key, Message: err.Error()})
+			continue
+		}
+		if s.typ != nil {
+			problems = append(problems, check(s.typ, raw, s.key)...)
+			continue
+		}
+		problems = append(problems, checkSchema(s.schema, raw, s.key)...)
+	}
+	if len(problems) > 0 {
+		return ErrValidateConfig(problems)
+	}
+	return nil
+}
+
+// Get returns the section under key of the configuration provided by handler as a T, with the missing fields set to
+// their default values. It reports all the problems found in the section, see ProblemsFromError.
+func Get[T any](handler config.Handler, key string) (*T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, ErrDecodeSection(key, fmt.Errorf("%s is not a struct", typ))
+	}
+	raw, err := read(handler, key)
+	if err != nil {
+		return nil, ErrDecodeSection(key, err)
+	}
+	if problems := check(typ, raw, key); len(problems) > 0 {
+		return nil, ErrValidateConfig(problems)
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, ErrDecodeSection(key, err)
+	}
+	result := new(T)
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, ErrDecodeSection(key, err)
+	}
+	return result, nil
+}
+
+// read returns the object under key, which is empty if the key is not set.
+func read(handler config.Handler, key string) (map[string]interface{}, error) {
+	if lookup, ok := handler.(config.KeyLookup); ok {
+		if _, set := lookup.LookupKey(key); !set {
+			return map[string]interface{}{}, nil
+		}
+	}
+	var raw map[string]interface{}
+	if err := handler.GetObject(key, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return raw, nil
+}
+
+type field struct {
+	name       string
+	typ        reflect.Type
+	def        string
+	hasDefault bool
+	required   bool
+}
+
+// fields returns the fields of the struct typ, flattening the embedded structs as encoding/json does.
+func fields(typ reflect.Type) []field {
+	result := []field{}
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			if embedded := structType(sf.Type); embedded != nil {
+				result = append(result, fields(embedded)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		def, hasDefault := sf.Tag.Lookup("default")
+		result = append(result, field{
+			name:       name,
+			typ:        sf.Type,
+			def:        def,
+			hasDefault: hasDefault,
+			required:   sf.Tag.Get("required") == "true",
+		})
+	}
+	return result
+}
+
+// check checks the object raw against the struct typ, and sets its missing fields to their default values.
+// Keys are matched case-insensitively, as by encoding/json, since some providers, e.g. Viper, lowercase them.
+func check(typ reflect.Type, raw map[string]interface{}, path string) []Problem {
+	problems := []Problem{}
+	known := map[string]bool{}
+	for _, f := range fields(typ) {
+		key := path + "." + f.name
+		rawKey, ok := findKey(raw, f.name)
+		if !ok {
+			nested := map[string]interface{}{}
+			var nestedProblems []Problem
+			if st := structType(f.typ); st != nil {
+				// sets the defaults of the nested fields, and finds their required keys
+				nestedProblems = check(st, nested, key)
+			}
+			switch {
+			case f.hasDefault:
+				value, err := parseDefault(f)
+				if err != nil {
+					problems = append(problems, Problem{Key: key, Message: fmt.Sprintf("invalid default value: %s", err)})
+					continue
+				}
+				raw[f.name] = value
+			case f.required && len(nested) == 0:
+				problems = append(problems, Problem{Key: key, Message: "required key is not set"})
+			default:
+				if len(nested) > 0 {
+					raw[f.name] = nested
+				}
+				// without a default, the required keys of a missing object are missing too
+				problems = append(problems, nestedProblems...)
+			}
+			continue
+		}
+		known[rawKey] = true
+
+		value := raw[rawKey]
+		if st := structType(f.typ); st != nil {
+			if object, ok := value.(map[string]interface{}); ok {
+				problems = append(problems, check(st, object, key)...)
+				continue
+			}
+		}
+		if err := checkType(value, f.typ); err != nil {
+			problems = append(problems, Problem{Key: key, Message: err.Error()})
+		}
+	}
+
+	unknown := []string{}
+	for rawKey := range raw {
+		if !known[rawKey] {
+			if _, ok := findField(typ, rawKey); !ok {
+				unknown = append(unknown, rawKey)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	for _, rawKey := range unknown {
+		problems = append(problems, Problem{Key: path + "." + rawKey, Message: "unknown key"})
+	}
+	return problems
+}
+
+func findKey(raw map[string]interface{}, name string) (string, bool) {
+	if _, ok := raw[name]; ok {
+		return name, true
+	}
+	for key := range raw {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func findField(typ reflect.Type, key string) (field, bool) {
+	for _, f := range fields(typ) {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// structType returns the struct typ, or the struct typ points to, or nil.
+func structType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	// structs encoded as strings or numbers, e.g. time.Time
+	if reflect.PointerTo(typ).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return nil
+	}
+	return typ
+}
+
+// parseDefault parses the default value of the field: as is for strings, as JSON otherwise.
+func parseDefault(f field) (interface{}, error) {
+	typ := f.typ
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.String {
+		return f.def, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(f.def), &value); err != nil {
+		return nil, err
+	}
+	if err := checkType(value, f.typ); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// checkType checks that value decodes into a typ.
+func checkType(value interface{}, typ reflect.Type) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, reflect.New(typ).Interface()); err != nil {
+		return fmt.Errorf("%s", strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return nil
+}
+
+// checkSchema checks the object raw against the CUE value of a JSON Schema.
+func checkSchema(schema cue.Value, raw map[string]interface{}, path string) []Problem {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return []Problem{{Key: path, Message: err.Error()}}
+	}
+	value, err := utils.JsonToCue(data)
+	if err != nil {
+		return []Problem{{Key: path, Message: err.Error()}}
+	}
+	problems := []Problem{}
+	if valid, errs := utils.Validate(schema, value); !valid {
+		for _, err := range errs {
+			key, message := path, err.Error()
+			if p := err.Path(); len(p) > 0 {
+				key = path + "." + strings.Join(p, ".")
+				message = strings.TrimPrefix(message, strings.Join(p, ".")+": ")
+			}
+			problems = append(problems, Problem{Key: key, Message: message})
+		}
+	}
+	return problems
+}
@@ -0,0 +1,119 @@
+package section
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/meshery/meshkit/config/provider"
+)
+
+type logConfig struct {
+	Level  string `json:"level" default:"info"`
+	Format string `json:"format" required:"true"`
+}
+
+type brokerConfig struct {
+	URL     string    `json:"url" required:"true"`
+	Retries int       `json:"retries" default:"3"`
+	Log     logConfig `json:"log"`
+}
+
+func newHandler(t *testing.T, objects map[string]interface{}) *provider.InMem {
+	t.Helper()
+	h, _ := provider.NewInMem(provider.Options{})
+	for key, object := range objects {
+		if err := h.SetObject(key, object); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return h.(*provider.InMem)
+}
+
+func TestGet(t *testing.T) {
+	h := newHandler(t, map[string]interface{}{
+		"broker": map[string]interface{}{"url": "nats://localhost:4222", "log": map[string]interface{}{"format": "json"}},
+	})
+	got, err := Get[brokerConfig](h, "broker")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := brokerConfig{URL: "nats://localhost:4222", Retries: 3, Log: logConfig{Level: "info", Format: "json"}}
+	if *got != expected {
+		t.Errorf("expected %+v, got %+v", expected, *got)
+	}
+}
+
+func TestGet_Problems(t *testing.T) {
+	h := newHandler(t, map[string]interface{}{
+		"broker": map[string]interface{}{"ulr": "nats://localhost:4222", "retries": "three", "log": map[string]interface{}{"level": "debug"}},
+	})
+	_, err := Get[brokerConfig](h, "broker")
+	problems, ok := ProblemsFromError(err)
+	if !ok {
+		t.Fatalf("expected validation problems, got %v", err)
+	}
+	keys := []string{}
+	for _, problem := range problems {
+		keys = append(keys, problem.Key)
+	}
+	expected := []string{"broker.url", "broker.retries", "broker.log.format", "broker.ulr"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected problems at %v, got %v", expected, problems)
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	r := NewRegistry()
+	if err := Register[brokerConfig](r, "broker"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.RegisterSchema("adapter", `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"port": {"type": "integer"}
+		},
+		"required": ["name"]
+	}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Register[logConfig](r, "broker"); err == nil {
+		t.Error("expected an error registering a section twice")
+	}
+	if err := Register[string](r, "name"); err == nil {
+		t.Error("expected an error registering a non-struct section")
+	}
+
+	valid := newHandler(t, map[string]interface{}{
+		"broker":  map[string]interface{}{"url": "nats://localhost:4222", "log": map[string]interface{}{"format": "json"}},
+		"adapter": map[string]interface{}{"name": "istio", "port": 10000},
+	})
+	if err := r.Validate(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Every problem of every section is reported at once.
+	invalid := newHandler(t, map[string]interface{}{
+		"broker":  map[string]interface{}{"retries": 1},
+		"adapter": map[string]interface{}{"port": "10000"},
+	})
+	problems, ok := ProblemsFromError(r.Validate(invalid))
+	if !ok {
+		t.Fatal("expected validation problems")
+	}
+	brokerKeys := []string{}
+	adapterProblems := 0
+	for _, problem := range problems {
+		switch section, _, _ := strings.Cut(problem.Key, "."); section {
+		case "broker":
+			brokerKeys = append(brokerKeys, problem.Key)
+		case "adapter":
+			adapterProblems++
+		}
+	}
+	// The required keys of the missing broker.log object are reported too.
+	if expected := []string{"broker.url", "broker.log.format"}; !reflect.DeepEqual(brokerKeys, expected) || adapterProblems < 1 {
+		t.Errorf("expected problems at %v and in the adapter section, got %v", expected, problems)
+	}
+}
@@ -250,6 +250,15 @@ func GetLDescription(err error) string {
 	return strings.Join(NoneString, "")
 }
 
+// GetAdditionalInfo returns the AdditionalInfo of an ErrorV2, or nil for other errors.
+func GetAdditionalInfo(err error) interface{} {
+	var errV2 *ErrorV2
+	if stderrors.As(err, &errV2) && errV2 != nil {
+		return errV2.AdditionalInfo
+	}
+	return nil
+}
+
 func Is(err error) (*Error, bool) {
 	if err != nil {
 		er, ok := err.(*Error)
@@ -25,6 +25,9 @@ func TestGettersSupportErrorV2(t *testing.T) {
 	assert.Equal(t, "long", GetLDescription(err))
 	assert.Equal(t, "cause", GetCause(err))
 	assert.Equal(t, "remedy", GetRemedy(err))
+	assert.Equal(t, map[string]string{"field": "value"}, GetAdditionalInfo(err))
+	assert.Equal(t, map[string]string{"field": "value"}, GetAdditionalInfo(fmt.Errorf("wrapped: %w", err)))
+	assert.Nil(t, GetAdditionalInfo(New("meshkit-99999", Alert, nil, nil, nil, nil)))
 }
 
 func TestGettersSupportWrappedErrorV2(t *testing.T) {
@@ -3,7 +3,6 @@ package logger
 import (
 	"github.com/go-logr/logr"
 	"github.com/meshery/meshkit/errors"
-	"github.com/sirupsen/logrus"
 )
 
 var (
@@ -17,6 +16,7 @@ func ErrController(err error, msg string) error {
 type Controller struct {
 	enabled bool
 	base    *Logger
+	name    string
 }
 
 func (l *Logger) ControllerLogger() logr.Logger {
@@ -34,25 +34,34 @@ func (c *Controller) Enabled(level int) bool {
 }
 
 func (c *Controller) Info(level int, msg string, keysAndValues ...interface{}) {
-	c.base.Info(msg)
+	c.base.with(keysAndValues...).Info(msg)
 }
 
 func (c *Controller) Error(err error, msg string, keysAndValues ...interface{}) {
-	c.base.Error(ErrController(err, msg))
+	c.base.with(keysAndValues...).Error(ErrController(err, msg))
 }
 
 func (c *Controller) V(level int) *Controller {
 	return c
 }
 
+// WithValues returns a sink logging the given key/value pairs with every entry.
 func (c *Controller) WithValues(keysAndValues ...interface{}) logr.LogSink {
-	c.base.defaultHandler.Log(logrus.InfoLevel, keysAndValues...)
-	c.base.errorHandler.Log(logrus.ErrorLevel, keysAndValues...)
-	return c
+	return &Controller{
+		enabled: c.enabled,
+		base:    c.base.with(keysAndValues...),
+		name:    c.name,
+	}
 }
 
+// WithName returns a sink logging the name, appended to the name of c with a dot, in the name field of every entry.
 func (c *Controller) WithName(name string) logr.LogSink {
-	c.base.defaultHandler.WithField("name", name)
-	c.base.errorHandler.WithField("name", name)
-	return c
+	if c.name != "" {
+		name = c.name + "." + name
+	}
+	return &Controller{
+		enabled: c.enabled,
+		base:    c.base.with("name", name),
+		name:    name,
+	}
 }
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -24,19 +25,13 @@ func (c *Database) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
 	return c
 }
 func (c *Database) Info(ctx context.Context, msg string, data ...interface{}) {
-	c.base.defaultHandler.Log(logrus.InfoLevel,
-		msg, data,
-	)
+	c.base.log(c.base.defaultHandler, logrus.InfoLevel, msg, fmt.Sprint(msg, data))
 }
 func (c *Database) Warn(ctx context.Context, msg string, data ...interface{}) {
-	c.base.defaultHandler.Log(logrus.WarnLevel,
-		msg, data,
-	)
+	c.base.log(c.base.defaultHandler, logrus.WarnLevel, msg, fmt.Sprint(msg, data))
 }
 func (c *Database) Error(ctx context.Context, msg string, data ...interface{}) {
-	c.base.errorHandler.Log(logrus.ErrorLevel,
-		msg, data,
-	)
+	c.base.log(c.base.errorHandler, logrus.ErrorLevel, msg, fmt.Sprint(msg, data))
 }
 func (c *Database) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
 }
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -23,6 +24,8 @@ type Handler interface {
 	Errorf(format string, args ...interface{})
 	Fatal(err error)
 	Fatalf(format string, args ...interface{})
+	// With returns a child Handler logging the given key/value pairs as fields of every entry
+	With(fields ...interface{}) Handler
 	SetLevel(level logrus.Level)
 	GetLevel() logrus.Level
 	UpdateLogOutput(w io.Writer)
@@ -35,6 +38,8 @@ type Handler interface {
 type Logger struct {
 	defaultHandler *logrus.Entry
 	errorHandler   *logrus.Entry
+	// sampler is shared by the logger and its children, nil if sampling is disabled
+	sampler *sampler
 }
 
 // TerminalFormatter is exported
@@ -100,27 +105,47 @@ func newLogrusLogger(appname string, opts Options, defaultOutput io.Writer) *log
 func New(appname string, opts Options) (Handler, error) {
 	entry := newLogrusLogger(appname, opts, os.Stdout)
 	errEntry := newLogrusLogger(appname, opts, os.Stderr)
-	return &Logger{defaultHandler: entry, errorHandler: errEntry}, nil
+	return &Logger{defaultHandler: entry, errorHandler: errEntry, sampler: newSampler(opts.Sampling)}, nil
+}
+
+// log logs the message at level through entry, unless sampled out. The entries with the same key, e.g. the same
+// format, are sampled together.
+func (l *Logger) log(entry *logrus.Entry, level logrus.Level, key, message string) {
+	if !entry.Logger.IsLevelEnabled(level) {
+		return
+	}
+	if l.sampler == nil {
+		entry.Log(level, message)
+		return
+	}
+	l.sampler.log(entry, level, key, message)
+}
+
+// fatal logs the message through entry and exits. Fatal entries are never sampled.
+func (l *Logger) fatal(entry *logrus.Entry, message string) {
+	if l.sampler != nil {
+		l.sampler.flushRepeated()
+	}
+	entry.Log(logrus.FatalLevel, message)
+	os.Exit(1)
 }
 
 func (l *Logger) Info(description ...interface{}) {
-	l.defaultHandler.Log(logrus.InfoLevel,
-		description...,
-	)
+	message := fmt.Sprint(description...)
+	l.log(l.defaultHandler, logrus.InfoLevel, message, message)
 }
 
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.defaultHandler.Log(logrus.InfoLevel, fmt.Sprintf(format, args...))
+	l.log(l.defaultHandler, logrus.InfoLevel, format, fmt.Sprintf(format, args...))
 }
 
 func (l *Logger) Debug(description ...interface{}) {
-	l.defaultHandler.Log(logrus.DebugLevel,
-		description...,
-	)
+	message := fmt.Sprint(description...)
+	l.log(l.defaultHandler, logrus.DebugLevel, message, message)
 }
 
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.defaultHandler.Log(logrus.DebugLevel, fmt.Sprintf(format, args...))
+	l.log(l.defaultHandler, logrus.DebugLevel, format, fmt.Sprintf(format, args...))
 }
 
 func (l *Logger) Warn(err error) {
@@ -128,17 +153,48 @@ func (l *Logger) Warn(err error) {
 		return
 	}
 
-	l.defaultHandler.WithFields(logrus.Fields{
-		"code":                  errors.GetCode(err),
-		"severity":              errors.GetSeverity(err),
-		"short-description":     errors.GetSDescription(err),
-		"probable-cause":        errors.GetCause(err),
-		"suggested-remediation": errors.GetRemedy(err),
-	}).Log(logrus.WarnLevel, err.Error())
+	l.log(l.defaultHandler.WithFields(errorFields(err)), logrus.WarnLevel, err.Error(), err.Error())
+}
+
+// legacyErrorFields maps the keys the metadata of MeshKit errors was logged under before the error.* keys to the
+// error.* keys.
+//
+// Deprecated: the legacy keys are logged along with the error.* keys, so that the dashboards and alerts relying on
+// them keep working while they move to the error.* keys. They will be removed in a future release.
+var legacyErrorFields = map[string]string{
+	"code":                  "error.code",
+	"severity":              "error.severity",
+	"short-description":     "error.short",
+	"probable-cause":        "error.cause",
+	"suggested-remediation": "error.remedy",
+}
+
+// errorFields returns the metadata of the MeshKit error err as log fields, so that log shippers can index them, e.g.
+// group the failures by error.code. The AdditionalInfo of an ErrorV2 is logged as is if it can be encoded as JSON,
+// and formatted otherwise. The metadata is also logged under the legacy keys, see legacyErrorFields.
+func errorFields(err error) logrus.Fields {
+	fields := logrus.Fields{
+		"error.code":     errors.GetCode(err),
+		"error.severity": errors.GetSeverity(err),
+		"error.short":    errors.GetSDescription(err),
+		"error.cause":    errors.GetCause(err),
+		"error.remedy":   errors.GetRemedy(err),
+	}
+	if info := errors.GetAdditionalInfo(err); info != nil {
+		if _, marshalErr := json.Marshal(info); marshalErr == nil {
+			fields["error.additional_info"] = info
+		} else {
+			fields["error.additional_info"] = fmt.Sprintf("%+v", info)
+		}
+	}
+	for legacy, key := range legacyErrorFields {
+		fields[legacy] = fields[key]
+	}
+	return fields
 }
 
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	l.defaultHandler.Log(logrus.WarnLevel, fmt.Sprintf(format, args...))
+	l.log(l.defaultHandler, logrus.WarnLevel, format, fmt.Sprintf(format, args...))
 }
 
 func (l *Logger) Error(err error) {
@@ -146,17 +202,11 @@ func (l *Logger) Error(err error) {
 		return
 	}
 
-	l.errorHandler.WithFields(logrus.Fields{
-		"code":                  errors.GetCode(err),
-		"severity":              errors.GetSeverity(err),
-		"short-description":     errors.GetSDescription(err),
-		"probable-cause":        errors.GetCause(err),
-		"suggested-remediation": errors.GetRemedy(err),
-	}).Log(logrus.ErrorLevel, err.Error())
+	l.log(l.errorHandler.WithFields(errorFields(err)), logrus.ErrorLevel, err.Error(), err.Error())
 }
 
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	l.errorHandler.Log(logrus.ErrorLevel, fmt.Sprintf(format, args...))
+	l.log(l.errorHandler, logrus.ErrorLevel, format, fmt.Sprintf(format, args...))
 }
 
 func (l *Logger) Fatal(err error) {
@@ -164,19 +214,50 @@ func (l *Logger) Fatal(err error) {
 		return
 	}
 
-	l.errorHandler.WithFields(logrus.Fields{
-		"code":                  errors.GetCode(err),
-		"severity":              errors.GetSeverity(err),
-		"short-description":     errors.GetSDescription(err),
-		"probable-cause":        errors.GetCause(err),
-		"suggested-remediation": errors.GetRemedy(err),
-	}).Log(logrus.FatalLevel, err.Error())
-	os.Exit(1)
+	l.fatal(l.errorHandler.WithFields(errorFields(err)), err.Error())
 }
 
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	l.errorHandler.Log(logrus.FatalLevel, fmt.Sprintf(format, args...))
-	os.Exit(1)
+	l.fatal(l.errorHandler, fmt.Sprintf(format, args...))
+}
+
+// With returns a child Handler logging the given fields with every entry, in addition to the fields of l.
+// The fields are given as key/value pairs, as for logr: With("subject", subject, "queue", queue).
+// With JsonLogFormat, they are emitted as top-level keys.
+//
+// The child shares the level and the outputs of l.
+func (l *Logger) With(fields ...interface{}) Handler {
+	return l.with(fields...)
+}
+
+func (l *Logger) with(keysAndValues ...interface{}) *Logger {
+	if len(keysAndValues) == 0 {
+		return l
+	}
+	fields := toFields(keysAndValues)
+	return &Logger{
+		defaultHandler: l.defaultHandler.WithFields(fields),
+		errorHandler:   l.errorHandler.WithFields(fields),
+		sampler:        l.sampler,
+	}
+}
+
+// toFields converts key/value pairs to logrus fields. Keys which are not strings are formatted, and a key without a
+// value is logged with a nil value.
+func toFields(keysAndValues []interface{}) logrus.Fields {
+	fields := make(logrus.Fields, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		var value interface{}
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fields[key] = value
+	}
+	return fields
 }
 
 func (l *Logger) SetLevel(level logrus.Level) {
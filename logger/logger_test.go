@@ -2,7 +2,10 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -191,3 +194,132 @@ func TestLoggerIntegration_FileOutput(t *testing.T) {
 	assert.Contains(t, string(data), "the probable cause of the error is Z")
 	assert.Contains(t, string(data), "try doing A, B, or C to remediate the error")
 }
+
+func decodeJSONLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	entries := []map[string]interface{}{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var entry map[string]interface{}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("Failed to parse log output as JSON: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger_With(t *testing.T) {
+	var outBuffer, errBuffer bytes.Buffer
+	log, err := New("testapp", Options{
+		Format:   JsonLogFormat,
+		LogLevel: int(logrus.InfoLevel),
+		Output:   &outBuffer,
+	})
+	assert.NoError(t, err)
+	log.UpdateErrorLogOutput(&errBuffer)
+
+	child := log.With("subject", "meshery.meshsync", "attempt", 2)
+	child.With("queue", "meshery").Info("subscribed")
+	child.Error(errors.New("disconnected"))
+	log.Info("parent")
+
+	entries := decodeJSONLines(t, &outBuffer)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "subscribed", entries[0]["msg"])
+	assert.Equal(t, "meshery.meshsync", entries[0]["subject"])
+	assert.Equal(t, float64(2), entries[0]["attempt"])
+	assert.Equal(t, "meshery", entries[0]["queue"])
+	assert.Equal(t, "testapp", entries[0]["app"])
+	assert.NotContains(t, entries[1], "subject", "the fields of a child must not leak to its parent")
+
+	errEntries := decodeJSONLines(t, &errBuffer)
+	assert.Len(t, errEntries, 1)
+	assert.Equal(t, "meshery.meshsync", errEntries[0]["subject"])
+}
+
+func TestLogger_ControllerLogger(t *testing.T) {
+	var outBuffer, errBuffer bytes.Buffer
+	log, err := New("testapp", Options{
+		Format:   JsonLogFormat,
+		LogLevel: int(logrus.InfoLevel),
+		Output:   &outBuffer,
+	})
+	assert.NoError(t, err)
+	log.UpdateErrorLogOutput(&errBuffer)
+
+	controller := log.With("component", "operator").ControllerLogger()
+	scoped := controller.WithName("broker").WithName("reconciler").WithValues("namespace", "meshery")
+	scoped.Info("reconciled", "name", "meshery-broker-0", "generation", 3)
+	scoped.Error(errors.New("conflict"), "update failed", "retry", true)
+	controller.Info("unscoped")
+
+	entries := decodeJSONLines(t, &outBuffer)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "reconciled", entries[0]["msg"])
+	assert.Equal(t, "operator", entries[0]["component"])
+	assert.Equal(t, "meshery", entries[0]["namespace"])
+	assert.Equal(t, float64(3), entries[0]["generation"])
+	// the name field of the entry is the key/value pair, which overrides the name of the sink
+	assert.Equal(t, "meshery-broker-0", entries[0]["name"])
+	assert.NotContains(t, entries[1], "namespace")
+
+	errEntries := decodeJSONLines(t, &errBuffer)
+	assert.Len(t, errEntries, 1)
+	assert.Equal(t, "broker.reconciler", errEntries[0]["name"])
+	assert.Equal(t, "meshery", errEntries[0]["namespace"])
+	assert.Equal(t, true, errEntries[0]["retry"])
+}
+
+func TestLogger_DatabaseLogger(t *testing.T) {
+	var outBuffer bytes.Buffer
+	log, err := New("testapp", Options{
+		Format:   JsonLogFormat,
+		LogLevel: int(logrus.InfoLevel),
+		Output:   &outBuffer,
+	})
+	assert.NoError(t, err)
+
+	log.With("database", "meshery").DatabaseLogger().Info(context.Background(), "migrated")
+
+	entries := decodeJSONLines(t, &outBuffer)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "meshery", entries[0]["database"])
+}
+
+func TestLogger_Error_MeshkitErrorFields(t *testing.T) {
+	var errBuffer bytes.Buffer
+	log, err := New("testapp", Options{
+		Format:   JsonLogFormat,
+		LogLevel: int(logrus.InfoLevel),
+	})
+	assert.NoError(t, err)
+	log.UpdateErrorLogOutput(&errBuffer)
+
+	log.Error(mError)
+	errV2 := mError.ErrorV2(map[string]interface{}{"instancePath": "/name", "badValue": 42})
+	log.Error(fmt.Errorf("validation: %w", &errV2))
+	unencodable := mError.ErrorV2(make(chan int))
+	log.Error(&unencodable)
+	log.Error(errors.New("plain error"))
+
+	entries := decodeJSONLines(t, &errBuffer)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, "code", entries[0]["error.code"])
+	assert.Equal(t, float64(meshkitError.Alert), entries[0]["error.severity"])
+	assert.Equal(t, "short test error occurred", entries[0]["error.short"])
+	assert.Equal(t, "the probable cause of the error is Z", entries[0]["error.cause"])
+	assert.Equal(t, "try doing A, B, or C to remediate the error", entries[0]["error.remedy"])
+	assert.NotContains(t, entries[0], "error.additional_info")
+	// The legacy keys are kept during their deprecation.
+	assert.Equal(t, "code", entries[0]["code"])
+	assert.Equal(t, float64(meshkitError.Alert), entries[0]["severity"])
+	assert.Equal(t, "short test error occurred", entries[0]["short-description"])
+	assert.Equal(t, "the probable cause of the error is Z", entries[0]["probable-cause"])
+	assert.Equal(t, "try doing A, B, or C to remediate the error", entries[0]["suggested-remediation"])
+
+	assert.Equal(t, "code", entries[1]["error.code"])
+	assert.Equal(t, map[string]interface{}{"instancePath": "/name", "badValue": float64(42)}, entries[1]["error.additional_info"])
+	assert.IsType(t, "", entries[2]["error.additional_info"])
+	assert.Equal(t, "None", entries[3]["error.code"])
+}
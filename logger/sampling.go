@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// defaultSamplingTick is the default period after which the sampling counters are reset.
+const defaultSamplingTick = time.Second
+
+// SamplingOptions reduces the volume of the log entries, e.g. of reconnect loops logging the same message thousands
+// of times per minute. Sampling applies before formatting, so it works with every format. Fatal entries are never
+// sampled.
+type SamplingOptions struct {
+	// Initial is the number of entries of a level with the same message logged per Tick, after which only every
+	// Thereafter-th one is logged, or none if Thereafter is 0. Entries logged with a format, e.g. by Infof, have the
+	// same message when they have the same format. Sampling by message is disabled when Initial is 0.
+	Initial    int
+	Thereafter int
+	// Tick is the period after which the counts of the messages are reset, one second by default.
+	Tick time.Duration
+
+	// RateLimits limits the number of entries of each level.
+	RateLimits map[logrus.Level]RateLimit
+
+	// DedupWindow collapses the identical consecutive entries logged within the window after the first of them: the
+	// first one is logged, and the others are summarized by an entry "<message> (repeated N times)" with a repeated
+	// field, once another entry is logged or the window ends. Deduplication is disabled when DedupWindow is 0.
+	DedupWindow time.Duration
+}
+
+// RateLimit is a token bucket: PerSecond entries per second, in bursts of up to Burst entries.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// repeatedEntry is the last entry logged, which is repeated while deduplicating.
+type repeatedEntry struct {
+	entry    *logrus.Entry
+	level    logrus.Level
+	message  string
+	repeated int
+	timer    *time.Timer
+}
+
+func (r *repeatedEntry) matches(entry *logrus.Entry, level logrus.Level, message string) bool {
+	return r.level == level && r.message == message && reflect.DeepEqual(r.entry.Data, entry.Data)
+}
+
+// sampler decides which entries are logged, for a logger and all its children.
+type sampler struct {
+	opts  SamplingOptions
+	now   func() time.Time
+	mutex sync.Mutex
+
+	tickStart time.Time
+	counts    map[string]int
+	limiters  map[logrus.Level]*rate.Limiter
+	last      *repeatedEntry
+}
+
+// newSampler returns the sampler of the options, or nil if sampling is disabled.
+func newSampler(opts *SamplingOptions) *sampler {
+	if opts == nil {
+		return nil
+	}
+	s := &sampler{
+		opts:     *opts,
+		now:      time.Now,
+		counts:   make(map[string]int),
+		limiters: make(map[logrus.Level]*rate.Limiter, len(opts.RateLimits)),
+	}
+	if s.opts.Tick <= 0 {
+		s.opts.Tick = defaultSamplingTick
+	}
+	for level, limit := range opts.RateLimits {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		s.limiters[level] = rate.NewLimiter(rate.Limit(limit.PerSecond), burst)
+	}
+	return s
+}
+
+// log logs the message at level through entry, unless it is sampled out. The entries with the same key are counted
+// together.
+func (s *sampler) log(entry *logrus.Entry, level logrus.Level, key, message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.last != nil {
+		if s.last.matches(entry, level, message) {
+			s.last.repeated++
+			return
+		}
+		s.flush()
+	}
+	if !s.sample(level, key) {
+		return
+	}
+
+	entry.Log(level, message)
+	if s.opts.DedupWindow > 0 {
+		last := &repeatedEntry{entry: entry, level: level, message: message}
+		last.timer = time.AfterFunc(s.opts.DedupWindow, func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			if s.last == last {
+				s.flush()
+			}
+		})
+		s.last = last
+	}
+}
+
+// sample tells whether an entry is logged, according to the sampling by message and to the rate limits.
+func (s *sampler) sample(level logrus.Level, key string) bool {
+	now := s.now()
+	if s.opts.Initial > 0 {
+		if now.Sub(s.tickStart) >= s.opts.Tick {
+			s.tickStart = now
+			clear(s.counts)
+		}
+		key = level.String() + ":" + key
+		s.counts[key]++
+		if n := s.counts[key] - s.opts.Initial; n > 0 && (s.opts.Thereafter <= 0 || n%s.opts.Thereafter != 0) {
+			return false
+		}
+	}
+	if limiter, ok := s.limiters[level]; ok && !limiter.AllowN(now, 1) {
+		return false
+	}
+	return true
+}
+
+// flush logs the summary of the repetitions of the last entry, if any, and stops deduplicating it.
+func (s *sampler) flush() {
+	last := s.last
+	if last == nil {
+		return
+	}
+	s.last = nil
+	last.timer.Stop()
+	if last.repeated > 0 {
+		last.entry.WithField("repeated", last.repeated).Log(last.level, fmt.Sprintf("%s (repeated %d times)", last.message, last.repeated))
+	}
+}
+
+// flushRepeated is flush acquiring the lock, e.g. before exiting.
+func (s *sampler) flushRepeated() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.flush()
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSampledLogger returns a logger sampling its entries with a clock controlled by the test.
+func newSampledLogger(t *testing.T, format Format, sampling SamplingOptions) (*Logger, *bytes.Buffer, *time.Time) {
+	t.Helper()
+	var buf bytes.Buffer
+	log, err := New("testapp", Options{
+		Format:   format,
+		LogLevel: int(logrus.DebugLevel),
+		Output:   &buf,
+		Sampling: &sampling,
+	})
+	require.NoError(t, err)
+	l := log.(*Logger)
+	l.UpdateErrorLogOutput(&buf)
+	now := time.Unix(0, 0)
+	l.sampler.now = func() time.Time { return now }
+	return l, &buf, &now
+}
+
+func lines(buf *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestSampling_InitialThereafter(t *testing.T) {
+	l, buf, now := newSampledLogger(t, TerminalLogFormat, SamplingOptions{Initial: 2, Thereafter: 3})
+
+	for i := 1; i <= 8; i++ {
+		l.Infof("reconnecting, attempt %d", i)
+	}
+	l.Info("connected")
+	assert.Equal(t, []string{
+		"reconnecting, attempt 1",
+		"reconnecting, attempt 2",
+		"reconnecting, attempt 5",
+		"reconnecting, attempt 8",
+		"connected",
+	}, lines(buf))
+
+	// The counts are reset every tick.
+	buf.Reset()
+	*now = now.Add(time.Second)
+	l.Infof("reconnecting, attempt %d", 9)
+	assert.Equal(t, []string{"reconnecting, attempt 9"}, lines(buf))
+}
+
+func TestSampling_RateLimits(t *testing.T) {
+	l, buf, now := newSampledLogger(t, TerminalLogFormat, SamplingOptions{
+		RateLimits: map[logrus.Level]RateLimit{logrus.WarnLevel: {PerSecond: 1, Burst: 2}},
+	})
+
+	for i := 0; i < 5; i++ {
+		l.Warnf("slow consumer %d", i)
+		l.Infof("published %d", i)
+	}
+	*now = now.Add(time.Second)
+	l.Warnf("slow consumer %d", 5)
+
+	output := buf.String()
+	assert.Equal(t, 3, strings.Count(output, "slow consumer"))
+	assert.Contains(t, output, "slow consumer 5")
+	assert.Equal(t, 5, strings.Count(output, "published"), "the other levels are not limited")
+}
+
+func TestSampling_Dedup(t *testing.T) {
+	for name, format := range map[string]Format{"json": JsonLogFormat, "syslog": SyslogLogFormat, "terminal": TerminalLogFormat} {
+		t.Run(name, func(t *testing.T) {
+			l, buf, _ := newSampledLogger(t, format, SamplingOptions{DedupWindow: time.Hour})
+
+			for i := 0; i < 4; i++ {
+				l.Error(errors.New("port forward lost"))
+			}
+			l.Info("port forward restored")
+
+			output := lines(buf)
+			require.Len(t, output, 3)
+			assert.Contains(t, output[0], "port forward lost")
+			assert.Contains(t, output[1], "port forward lost (repeated 3 times)")
+			assert.Contains(t, output[2], "port forward restored")
+		})
+	}
+}
+
+func TestSampling_DedupWindow(t *testing.T) {
+	l, buf, _ := newSampledLogger(t, JsonLogFormat, SamplingOptions{DedupWindow: 10 * time.Millisecond})
+
+	child := l.With("subject", "meshery.meshsync")
+	child.Info("subscribed")
+	l.Info("subscribed") // different fields, not a repetition
+	l.Info("subscribed")
+	l.Info("subscribed")
+
+	// The repetitions are summarized when the window ends.
+	assert.Eventually(t, func() bool {
+		l.sampler.mutex.Lock()
+		defer l.sampler.mutex.Unlock()
+		return l.sampler.last == nil
+	}, time.Second, 5*time.Millisecond)
+	entries := decodeJSONLines(t, buf)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "meshery.meshsync", entries[0]["subject"])
+	assert.Equal(t, "subscribed", entries[1]["msg"])
+	assert.NotContains(t, entries[1], "subject")
+	assert.Equal(t, "subscribed (repeated 2 times)", entries[2]["msg"])
+	assert.Equal(t, float64(2), entries[2]["repeated"])
+}
+
+func TestSampling_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New("testapp", Options{Format: TerminalLogFormat, LogLevel: int(logrus.InfoLevel), Output: &buf})
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		log.Info("same message")
+	}
+	log.Debug("below the level")
+	assert.Len(t, lines(&buf), 100)
+}
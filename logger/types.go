@@ -19,4 +19,6 @@ type Options struct {
 	ErrorOutput        io.Writer
 	EnableCallerInfo   bool
 	CallerSkippedPaths []string
+	// Sampling reduces the volume of repeated log entries, it is disabled when nil
+	Sampling *SamplingOptions
 }
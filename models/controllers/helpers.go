@@ -11,6 +11,7 @@ import (
 	"net/url"
 
 	"github.com/meshery/meshery-operator/api/v1alpha1"
+	"github.com/meshery/meshkit/broker"
 	"github.com/meshery/meshkit/utils"
 	mesherykube "github.com/meshery/meshkit/utils/kubernetes"
 )
@@ -28,6 +29,14 @@ type connection struct {
 	Name string `json:"name"`
 }
 
+// BrokerHealthStatus returns the status of a deployed broker given the health of the connection to it.
+func BrokerHealthStatus(health broker.Health) MesheryControllerStatus {
+	if health.Connected {
+		return Connected
+	}
+	return Deployed
+}
+
 // parseHostPort splits a "host:port" string into a HostPort. It tolerates an
 // empty string and a bare host (returning ok=false) instead of panicking, which
 // the previous strings.Split(...)[1] indexing did when an endpoint was empty.
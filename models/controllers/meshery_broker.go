@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	opClient "github.com/meshery/meshery-operator/pkg/client"
+	"github.com/meshery/meshkit/broker"
 	"github.com/meshery/meshkit/logger"
 	"github.com/meshery/meshkit/utils"
 	mesherykube "github.com/meshery/meshkit/utils/kubernetes"
@@ -32,6 +33,8 @@ type mesheryBroker struct {
 	name    string
 	status  MesheryControllerStatus
 	kclient *mesherykube.Client
+	// conn is the connection of Meshery Server to the broker, if any
+	conn broker.Handler
 }
 
 func NewMesheryBrokerHandler(kubernetesClient *mesherykube.Client) IMesheryController {
@@ -42,6 +45,17 @@ func NewMesheryBrokerHandler(kubernetesClient *mesherykube.Client) IMesheryContr
 	}
 }
 
+// NewMesheryBrokerHandlerWithConnection returns the controller of a broker Meshery Server is connected to through conn.
+// Its status reflects the health of conn, instead of probing the monitoring endpoint of the broker.
+func NewMesheryBrokerHandlerWithConnection(kubernetesClient *mesherykube.Client, conn broker.Handler) IMesheryController {
+	return &mesheryBroker{
+		name:    "MesheryBroker",
+		status:  Unknown,
+		kclient: kubernetesClient,
+		conn:    conn,
+	}
+}
+
 func (mb *mesheryBroker) GetName() string {
 	return mb.name
 }
@@ -57,6 +71,10 @@ func (mb *mesheryBroker) GetStatus() MesheryControllerStatus {
 	// TODO: Confirm if the presence of operator is needed to use the operator client sdk
 	_, err = operatorClient.CoreV1Alpha1().Brokers("meshery").Get(context.TODO(), "meshery-broker", metav1.GetOptions{})
 	if err == nil {
+		if mb.conn != nil {
+			mb.status = BrokerHealthStatus(mb.conn.Health())
+			return mb.status
+		}
 		var monitoringEndpoint string
 		monitoringEndpoint, err = mb.GetEndpointForPort(brokerMonitoringPortName)
 		if err == nil {
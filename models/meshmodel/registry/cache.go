@@ -0,0 +1,251 @@
+package registry
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+	"weak"
+
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+)
+
+type EntityCacheValue struct {
+	Entities []entity.Entity
+	Count    int64
+	Unique   int
+	err      error
+}
+
+// RegistryEntityCacheOptions bounds the size and the lifetime of the entries of a RegistryEntityCache.
+type RegistryEntityCacheOptions struct {
+	// MaxEntries is the maximum number of entries kept in the cache, the least recently used entry is evicted first.
+	// If 0, the number of entries is not bounded.
+	MaxEntries int
+	// TTL is the duration after which an entry expires. If 0, entries do not expire.
+	TTL time.Duration
+}
+
+// RegistryEntityCacheStats is a snapshot of the metrics of a RegistryEntityCache.
+type RegistryEntityCacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	Entries       int
+}
+
+type cacheEntry struct {
+	key       string
+	value     EntityCacheValue
+	types     map[entity.EntityType]struct{}
+	expiresAt time.Time
+}
+
+// RegistryEntityCache is a thread-safe cache for storing entity query results.
+//
+// This cache maps entity filters (`entity.Filter`) to their corresponding results (`EntityCacheValue`).
+// Filters are keyed by their type and the canonical JSON representation of their fields, so pointer-valued filters
+// with the same field values share an entry.
+//
+// The zero value is an unbounded cache whose entries never expire. Use NewRegistryEntityCache to bound the cache
+// using LRU eviction and/or a TTL. Caches used with `RegistryManager.GetEntitiesMemoized` are invalidated by the
+// RegistryManager whenever entities are registered or their status is updated.
+type RegistryEntityCache struct {
+	mu      sync.Mutex
+	opts    RegistryEntityCacheOptions
+	entries map[string]*list.Element
+	lru     *list.List // front is the most recently used entry
+	stats   RegistryEntityCacheStats
+}
+
+// NewRegistryEntityCache creates a cache bounded by the given options.
+func NewRegistryEntityCache(opts RegistryEntityCacheOptions) *RegistryEntityCache {
+	return &RegistryEntityCache{opts: opts}
+}
+
+func filterKey(f entity.Filter) string {
+	// Filters are plain structs, marshalling them dereferences pointers and yields a stable field order.
+	byt, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Sprintf("%T%+v", f, f)
+	}
+	return fmt.Sprintf("%T%s", f, byt)
+}
+
+func (c *RegistryEntityCache) init() {
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.lru = list.New()
+	}
+}
+
+// Get retrieves a cached value
+func (c *RegistryEntityCache) Get(f entity.Filter) (EntityCacheValue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	elem, exists := c.entries[filterKey(f)]
+	if !exists {
+		c.stats.Misses++
+		return EntityCacheValue{}, false
+	}
+	e := elem.Value.(*cacheEntry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.remove(elem)
+		c.stats.Evictions++
+		c.stats.Misses++
+		return EntityCacheValue{}, false
+	}
+	c.lru.MoveToFront(elem)
+	c.stats.Hits++
+	return e.value, true
+}
+
+// Set stores a value in the cache
+func (c *RegistryEntityCache) Set(f entity.Filter, value EntityCacheValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	e := &cacheEntry{
+		key:   filterKey(f),
+		value: value,
+		types: make(map[entity.EntityType]struct{}),
+	}
+	for _, en := range value.Entities {
+		if en != nil {
+			e.types[en.Type()] = struct{}{}
+		}
+	}
+	if c.opts.TTL > 0 {
+		e.expiresAt = time.Now().Add(c.opts.TTL)
+	}
+
+	if elem, exists := c.entries[e.key]; exists {
+		elem.Value = e
+		c.lru.MoveToFront(elem)
+	} else {
+		c.entries[e.key] = c.lru.PushFront(e)
+	}
+
+	for c.opts.MaxEntries > 0 && c.lru.Len() > c.opts.MaxEntries {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Invalidate removes the cached value of the given filter.
+func (c *RegistryEntityCache) Invalidate(f entity.Filter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	if elem, exists := c.entries[filterKey(f)]; exists {
+		c.remove(elem)
+		c.stats.Invalidations++
+	}
+}
+
+// InvalidateEntityType removes every cached value containing entities of the given types.
+func (c *RegistryEntityCache) InvalidateEntityType(types ...entity.EntityType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		e := elem.Value.(*cacheEntry)
+		for _, t := range types {
+			if _, ok := e.types[t]; ok {
+				c.remove(elem)
+				c.stats.Invalidations++
+				break
+			}
+		}
+		elem = next
+	}
+}
+
+// Purge removes every cached value.
+func (c *RegistryEntityCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	c.stats.Invalidations += uint64(c.lru.Len())
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// Stats returns a snapshot of the cache metrics.
+func (c *RegistryEntityCache) Stats() RegistryEntityCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+func (c *RegistryEntityCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// entityCaches tracks the caches used with a RegistryManager, so that they can be invalidated on changes to the registry.
+// It is shared by a RegistryManager and the RegistryManagers bound to its transactions.
+// Caches are tracked through weak pointers, short-lived caches (e.g. created per request) are not kept alive by the RegistryManager.
+type entityCaches struct {
+	mu     sync.Mutex
+	caches map[weak.Pointer[RegistryEntityCache]]struct{}
+}
+
+func (ec *entityCaches) track(c *RegistryEntityCache) {
+	if ec == nil || c == nil {
+		return
+	}
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.caches == nil {
+		ec.caches = make(map[weak.Pointer[RegistryEntityCache]]struct{})
+	}
+	wp := weak.Make(c)
+	if _, ok := ec.caches[wp]; ok {
+		return
+	}
+	// Drop the caches which have been garbage collected before tracking a new one.
+	for tracked := range ec.caches {
+		if tracked.Value() == nil {
+			delete(ec.caches, tracked)
+		}
+	}
+	ec.caches[wp] = struct{}{}
+}
+
+// invalidate drops the cached values affected by a change to an entity of the given type.
+// Models embed the counts and details of their components and relationships, while every other entity is
+// queried joined with its model, hence a change to a model invalidates every cached value.
+func (ec *entityCaches) invalidate(t entity.EntityType) {
+	if ec == nil {
+		return
+	}
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	for wp := range ec.caches {
+		c := wp.Value()
+		if c == nil {
+			// The cache has been garbage collected.
+			delete(ec.caches, wp)
+			continue
+		}
+		if t == entity.Model {
+			c.Purge()
+			continue
+		}
+		c.InvalidateEntityType(t, entity.Model)
+	}
+}
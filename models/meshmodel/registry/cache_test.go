@@ -0,0 +1,114 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/meshery/meshkit/database"
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	core "github.com/meshery/schemas/models/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFilter struct {
+	Name    string
+	Version *string
+}
+
+func (f *testFilter) Create(map[string]interface{}) {}
+
+func (f *testFilter) Get(*database.Handler) ([]entity.Entity, int64, int, error) {
+	return nil, 0, 0, nil
+}
+
+func (f *testFilter) GetById(*database.Handler) (entity.Entity, error) {
+	return nil, nil
+}
+
+type testEntity struct {
+	entityType entity.EntityType
+}
+
+func (e testEntity) Type() entity.EntityType        { return e.entityType }
+func (e testEntity) GetEntityDetail() string        { return "" }
+func (e testEntity) GenerateID() (core.Uuid, error) { return core.Uuid{}, nil }
+func (e testEntity) GetID() core.Uuid               { return core.Uuid{} }
+func (e testEntity) Create(*database.Handler, core.Uuid) (core.Uuid, error) {
+	return core.Uuid{}, nil
+}
+
+func cacheValue(types ...entity.EntityType) EntityCacheValue {
+	value := EntityCacheValue{}
+	for _, t := range types {
+		value.Entities = append(value.Entities, testEntity{entityType: t})
+	}
+	value.Count = int64(len(value.Entities))
+	return value
+}
+
+func TestRegistryEntityCacheCanonicalKey(t *testing.T) {
+	cache := &RegistryEntityCache{}
+	v1, v2 := "v1.0.0", "v1.0.0"
+
+	cache.Set(&testFilter{Name: "pod", Version: &v1}, cacheValue(entity.ComponentDefinition))
+
+	_, ok := cache.Get(&testFilter{Name: "pod", Version: &v2})
+	assert.True(t, ok, "filters with equal fields must share an entry")
+	_, ok = cache.Get(&testFilter{Name: "pod"})
+	assert.False(t, ok)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestRegistryEntityCacheLRU(t *testing.T) {
+	cache := NewRegistryEntityCache(RegistryEntityCacheOptions{MaxEntries: 2})
+
+	cache.Set(&testFilter{Name: "a"}, cacheValue(entity.ComponentDefinition))
+	cache.Set(&testFilter{Name: "b"}, cacheValue(entity.ComponentDefinition))
+	_, ok := cache.Get(&testFilter{Name: "a"})
+	require.True(t, ok)
+	cache.Set(&testFilter{Name: "c"}, cacheValue(entity.ComponentDefinition))
+
+	_, ok = cache.Get(&testFilter{Name: "b"})
+	assert.False(t, ok, "least recently used entry must be evicted")
+	_, ok = cache.Get(&testFilter{Name: "a"})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), cache.Stats().Evictions)
+}
+
+func TestRegistryEntityCacheTTL(t *testing.T) {
+	cache := NewRegistryEntityCache(RegistryEntityCacheOptions{TTL: time.Millisecond})
+
+	cache.Set(&testFilter{Name: "a"}, cacheValue(entity.ComponentDefinition))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := cache.Get(&testFilter{Name: "a"})
+	assert.False(t, ok)
+	assert.Zero(t, cache.Stats().Entries)
+}
+
+func TestRegistryManagerInvalidatesTrackedCaches(t *testing.T) {
+	rm := &RegistryManager{caches: &entityCaches{}}
+	cache := &RegistryEntityCache{}
+	rm.caches.track(cache)
+
+	cache.Set(&testFilter{Name: "components"}, cacheValue(entity.ComponentDefinition))
+	cache.Set(&testFilter{Name: "relationships"}, cacheValue(entity.RelationshipDefinition))
+	cache.Set(&testFilter{Name: "models"}, cacheValue(entity.Model))
+
+	rm.caches.invalidate(entity.ComponentDefinition)
+
+	_, ok := cache.Get(&testFilter{Name: "components"})
+	assert.False(t, ok)
+	_, ok = cache.Get(&testFilter{Name: "models"})
+	assert.False(t, ok, "models embed component counts")
+	_, ok = cache.Get(&testFilter{Name: "relationships"})
+	assert.True(t, ok)
+
+	rm.caches.invalidate(entity.Model)
+	assert.Zero(t, cache.Stats().Entries)
+}
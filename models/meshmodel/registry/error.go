@@ -15,6 +15,9 @@ var (
 	ErrUnknownHostInMapCode            = "meshkit-11260"
 	ErrCreatingUserDataDirectoryCode   = "meshkit-11261"
 	ErrGetByIdCode                     = "meshkit-11262"
+	ErrUnregisterModelCode             = "meshkit-11331"
+	ErrUnregisterRegistrantCode        = "meshkit-11332"
+	ErrSearchRegistryCode              = "meshkit-11334"
 )
 
 func ErrGetById(err error, id string) error {
@@ -52,3 +55,36 @@ func ErrRegisteringEntity(failedMsg string, hostName string) error {
 func ErrCreatingUserDataDirectory(dir string) error {
 	return errors.New(ErrCreatingUserDataDirectoryCode, errors.Fatal, []string{"Unable to create the directory for storing user data at: ", dir}, []string{"Unable to create the directory for storing user data at: ", dir}, []string{}, []string{})
 }
+
+func ErrUnregisterModel(err error, name, version string) error {
+	return errors.New(
+		ErrUnregisterModelCode,
+		errors.Alert,
+		[]string{fmt.Sprintf("Failed to unregister model: %s, version: %s", name, version)},
+		[]string{err.Error()},
+		[]string{"Registry might be inaccessible at the moment", "SVG files of the model might not be removable"},
+		[]string{"Try again after some time", "Check the permissions of the SVG directory"},
+	)
+}
+
+func ErrUnregisterRegistrant(err error, id string) error {
+	return errors.New(
+		ErrUnregisterRegistrantCode,
+		errors.Alert,
+		[]string{"Failed to unregister the registrant with the given ID: " + id},
+		[]string{err.Error()},
+		[]string{"The given ID might not be a valid UUID", "Registry might be inaccessible at the moment", "SVG files of the models might not be removable"},
+		[]string{"Check if your ID is correct", "Try again after some time", "Check the permissions of the SVG directory"},
+	)
+}
+
+func ErrSearchRegistry(err error) error {
+	return errors.New(
+		ErrSearchRegistryCode,
+		errors.Alert,
+		[]string{"Failed to search the registry"},
+		[]string{err.Error()},
+		[]string{"Registry might be inaccessible at the moment", "The search index might be out of date"},
+		[]string{"Try again after some time", "Rebuild the search index using RebuildSearchIndex"},
+	)
+}
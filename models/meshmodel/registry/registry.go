@@ -2,8 +2,8 @@ package registry
 
 import (
 	"fmt"
+	"log"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -19,6 +19,7 @@ import (
 	connectionv1beta3 "github.com/meshery/schemas/models/v1beta3/connection"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -35,42 +36,6 @@ type MeshModelRegistrantData struct {
 	Entity     []byte                       `json:"entity"` //This will be type converted to appropriate entity on server based on passed entity type
 }
 
-type EntityCacheValue struct {
-	Entities []entity.Entity
-	Count    int64
-	Unique   int
-	err      error
-}
-
-// RegistryEntityCache is a thread-safe cache for storing entity query results.
-//
-// This cache maps entity filters (`entity.Filter`) to their corresponding results (`EntityCacheValue`).
-// It uses `sync.Map` for safe concurrent access, making it suitable for multi-goroutine environments.
-//
-// The caller is responsible for managing the cache's lifecycle, including eviction and expiration if needed.
-type RegistryEntityCache struct {
-	cache sync.Map
-}
-
-func filterKey(f entity.Filter) string {
-	// Convert the filter to a unique string representation (adjust as needed)
-	return fmt.Sprintf("%v", f)
-}
-
-// Get retrieves a cached value
-func (c *RegistryEntityCache) Get(f entity.Filter) (EntityCacheValue, bool) {
-	value, exists := c.cache.Load(filterKey(f))
-	if exists {
-		return value.(EntityCacheValue), true
-	}
-	return EntityCacheValue{}, false
-}
-
-// Set stores a value in the cache
-func (c *RegistryEntityCache) Set(f entity.Filter, value EntityCacheValue) {
-	c.cache.Store(filterKey(f), value)
-}
-
 type Registry struct {
 	ID           core.Uuid
 	RegistrantID core.Uuid
@@ -82,7 +47,11 @@ type Registry struct {
 
 // RegistryManager instance will expose methods for registry operations & sits between the database level operations and user facing API handlers.
 type RegistryManager struct {
-	db *database.Handler //This database handler will be used to perform queries inside the database
+	db     *database.Handler //This database handler will be used to perform queries inside the database
+	caches *entityCaches     //Caches used with GetEntitiesMemoized, invalidated whenever the registry changes
+	fts    bool              //Whether the SQLite FTS5 search index is available
+	// Actions deferred until the transaction the manager is bound to is committed, nil outside transactions
+	afterCommit *[]func(rm *RegistryManager)
 }
 
 // NewRegistryManager initializes the registry manager by creating appropriate tables.
@@ -92,7 +61,8 @@ func NewRegistryManager(db *database.Handler) (*RegistryManager, error) {
 		return nil, fmt.Errorf("nil database handler")
 	}
 	rm := RegistryManager{
-		db: db,
+		db:     db,
+		caches: &entityCaches{},
 	}
 	err := rm.db.AutoMigrate(
 		&Registry{},
@@ -103,10 +73,16 @@ func NewRegistryManager(db *database.Handler) (*RegistryManager, error) {
 		&models.PolicyDefinition{},
 		&model.ModelDefinition{},
 		&category.CategoryDefinition{},
+		&searchDocument{},
 	)
 	if err != nil {
 		return nil, err
 	}
+	if err := rm.setupSearch(); err != nil {
+		// The search index is optional, Search falls back to LIKE matching without it.
+		rm.fts = false
+		log.Printf("failed to set up the registry search index, falling back to LIKE matching: %v", err)
+	}
 	return &rm, nil
 }
 func (rm *RegistryManager) Cleanup() {
@@ -118,6 +94,8 @@ func (rm *RegistryManager) Cleanup() {
 		&model.ModelDefinition{},
 		&category.CategoryDefinition{},
 		&relationship.RelationshipDefinition{},
+		searchFTSTable,
+		&searchDocument{},
 	)
 }
 func (rm *RegistryManager) RegisterEntity(h connectionv1beta3.Connection, en entity.Entity) (bool, bool, error) {
@@ -154,9 +132,43 @@ func (rm *RegistryManager) RegisterEntity(h connectionv1beta3.Connection, en ent
 	if err != nil {
 		return false, false, err
 	}
+	rm.onCommit(func(rm *RegistryManager) {
+		rm.caches.invalidate(en.Type())
+		// A stale search index does not affect the registration, it can be recovered using RebuildSearchIndex.
+		_ = rm.indexEntity(entityID, en)
+	})
 	return false, false, nil
 }
 
+// Transaction runs fn with a RegistryManager bound to a single database transaction.
+// The transaction is committed when fn returns nil and rolled back otherwise.
+// Calling Transaction on a RegistryManager that is already bound to a transaction
+// creates a savepoint, so only the changes made inside fn are rolled back on failure.
+// The caches and the search index are only updated once the changes are committed.
+func (rm *RegistryManager) Transaction(fn func(txm *RegistryManager) error) error {
+	var afterCommit []func(rm *RegistryManager)
+	err := rm.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&RegistryManager{db: &database.Handler{DB: tx, Mutex: rm.db.Mutex}, caches: rm.caches, fts: rm.fts, afterCommit: &afterCommit})
+	})
+	if err != nil {
+		return err
+	}
+	rm.onCommit(afterCommit...)
+	return nil
+}
+
+// onCommit runs the actions once the changes made through the manager are committed: right away outside transactions,
+// otherwise once the transaction the manager is bound to is committed, with the manager the transaction was started from.
+func (rm *RegistryManager) onCommit(actions ...func(rm *RegistryManager)) {
+	if rm.afterCommit != nil {
+		*rm.afterCommit = append(*rm.afterCommit, actions...)
+		return
+	}
+	for _, action := range actions {
+		action(rm)
+	}
+}
+
 // UpdateEntityStatus updates the ignore status of an entity based on the provided parameters.
 // By default during models generation ignore is set to false
 func (rm *RegistryManager) UpdateEntityStatus(ID string, status string, entityType string) error {
@@ -172,6 +184,7 @@ func (rm *RegistryManager) UpdateEntityStatus(ID string, status string, entityTy
 		if err != nil {
 			return err
 		}
+		rm.onCommit(func(rm *RegistryManager) { rm.caches.invalidate(entity.Model) })
 		return nil
 	default:
 		return nil
@@ -306,17 +319,18 @@ func (rm *RegistryManager) GetEntities(f entity.Filter) ([]entity.Entity, int64,
 //     stores the result in the `cache`, and returns the newly retrieved entities.
 //
 // ## Concurrency and Thread Safety:
-// - The `cache` is implemented using `sync.Map`, ensuring **safe concurrent access** across multiple goroutines.
-// - `sync.Map` is optimized for scenarios where **reads significantly outnumber writes**, making it well-suited for caching use cases.
+// - The `cache` is safe for concurrent access across multiple goroutines.
 //
 // ## Ownership and Responsibility:
 //   - **RegistryManager (`rm`)**: Owns the logic for retrieving entities from the source when a cache miss occurs.
-//   - **Caller Ownership of Cache**: The caller is responsible for providing and managing the `cache` instance.
-//     This function does *not* handle cache eviction, expiration, or memory constraints—those concerns must be managed externally.
+//     Every cache passed to this function is tracked by `rm`, and the affected entries are invalidated whenever
+//     entities are registered through `RegisterEntity`, unregistered, or their status is updated through `UpdateEntityStatus`.
+//   - **Caller Ownership of Cache**: The caller is responsible for providing the `cache` instance.
+//     Eviction and expiration are configured when creating the cache, see `NewRegistryEntityCache`.
 //
 // ## Parameters:
 // - `f entity.Filter`: The filter criteria used to retrieve entities.
-// - `cache *RegistryEntityCache`: A pointer to a concurrent-safe cache (`sync.Map`) that stores previously retrieved entity results.
+// - `cache *RegistryEntityCache`: A pointer to a concurrent-safe cache that stores previously retrieved entity results.
 //
 // ## Returns:
 // - `[]entity.Entity`: The list of retrieved entities (either from cache or freshly fetched).
@@ -324,6 +338,7 @@ func (rm *RegistryManager) GetEntities(f entity.Filter) ([]entity.Entity, int64,
 // - `int`: The number of unique entities found.
 // - `error`: An error if the retrieval operation fails.
 func (rm *RegistryManager) GetEntitiesMemoized(f entity.Filter, cache *RegistryEntityCache) ([]entity.Entity, int64, int, error) {
+	rm.caches.track(cache)
 
 	// Attempt to retrieve from cache
 	if cachedEntities, exists := cache.Get(f); exists && len(cachedEntities.Entities) > 0 {
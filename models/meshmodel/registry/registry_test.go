@@ -1,11 +1,14 @@
 package registry
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gofrs/uuid"
 	"github.com/meshery/meshkit/database"
 	"github.com/meshery/meshkit/models/meshmodel/entity"
+	core "github.com/meshery/schemas/models/core"
 	"github.com/meshery/schemas/models/v1beta1"
 	"github.com/meshery/schemas/models/v1beta1/category"
 	"github.com/meshery/schemas/models/v1beta1/model"
@@ -62,3 +65,107 @@ func TestUpdateEntityStatusReturnsErrorForInvalidUUID(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	db, err := database.New(database.Options{
+		Engine:   database.SQLITE,
+		Filename: ":memory:",
+	})
+	require.NoError(t, err)
+
+	rm, err := NewRegistryManager(&db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		rm.Cleanup()
+		assert.NoError(t, db.DBClose())
+	})
+
+	modelDef := model.ModelDefinition{
+		SchemaVersion: v1beta1.ModelSchemaVersion,
+		Version:       "1.0.0",
+		Name:          "rolled-back-model",
+		DisplayName:   "Rolled Back Model",
+		Status:        model.Enabled,
+		Category: category.CategoryDefinition{
+			Name: "test-category",
+		},
+		Model: model.Model{
+			Version: "1.0.0",
+		},
+	}
+
+	err = rm.Transaction(func(txm *RegistryManager) error {
+		if _, err := modelDef.Create(txm.db, uuid.Must(uuid.NewV4())); err != nil {
+			return err
+		}
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	var count int64
+	require.NoError(t, db.Model(&model.ModelDefinition{}).Where("name = ?", modelDef.Name).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestUnregisterModel(t *testing.T) {
+	db, err := database.New(database.Options{
+		Engine:   database.SQLITE,
+		Filename: ":memory:",
+	})
+	require.NoError(t, err)
+
+	rm, err := NewRegistryManager(&db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		rm.Cleanup()
+		assert.NoError(t, db.DBClose())
+	})
+
+	hostID := uuid.Must(uuid.NewV4())
+	modelDef := model.ModelDefinition{
+		SchemaVersion: v1beta1.ModelSchemaVersion,
+		Version:       "1.0.0",
+		Name:          "test-model",
+		DisplayName:   "Test Model",
+		Status:        model.Enabled,
+		Category: category.CategoryDefinition{
+			Name: "test-category",
+		},
+		Model: model.Model{
+			Version: "1.0.0",
+		},
+	}
+	modelID, err := modelDef.Create(&db, hostID)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&Registry{
+		ID:           uuid.Must(uuid.NewV4()),
+		RegistrantID: hostID,
+		Entity:       modelID,
+		Type:         entity.Model,
+	}).Error)
+
+	svgBaseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(svgBaseDir, "test-model", "color"), 0755))
+	opts := UnregisterOptions{SVGBaseDir: svgBaseDir, DryRun: true}
+
+	report, err := rm.UnregisterModel("test-model", "1.0.0", opts)
+	require.NoError(t, err)
+	assert.Equal(t, []core.Uuid{modelID}, report.Models)
+	assert.Equal(t, int64(1), report.Registries)
+	assert.Equal(t, []string{filepath.Join(svgBaseDir, "test-model")}, report.SVGPaths)
+
+	var count int64
+	require.NoError(t, db.Model(&model.ModelDefinition{}).Where("id = ?", modelID).Count(&count).Error)
+	assert.Equal(t, int64(1), count, "dry run must not delete anything")
+
+	opts.DryRun = false
+	report, err = rm.UnregisterModel("test-model", "1.0.0", opts)
+	require.NoError(t, err)
+	assert.Empty(t, report.SVGErrors)
+
+	require.NoError(t, db.Model(&model.ModelDefinition{}).Where("id = ?", modelID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Model(&Registry{}).Where("entity = ?", modelID).Count(&count).Error)
+	assert.Zero(t, count)
+	assert.NoDirExists(t, filepath.Join(svgBaseDir, "test-model"))
+}
@@ -0,0 +1,423 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/meshery/meshkit/database"
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	core "github.com/meshery/schemas/models/core"
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	"github.com/meshery/schemas/models/v1beta1/category"
+	"github.com/meshery/schemas/models/v1beta1/model"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const (
+	searchDocumentTable = "search_document_dbs"
+	searchFTSTable      = "search_document_fts"
+)
+
+// searchDocument is the denormalized representation of a registry entity used for searching.
+// A document is kept per model, component, relationship and category.
+type searchDocument struct {
+	ID          uint              `gorm:"primaryKey"`
+	EntityID    core.Uuid         `gorm:"index"`
+	EntityType  entity.EntityType `gorm:"index"`
+	Name        string
+	DisplayName string
+	Description string
+	Properties  string // space separated property names of the component schema
+	ModelName   string `gorm:"index"`
+	Category    string `gorm:"index"`
+	Registrant  string `gorm:"index"`
+}
+
+func (searchDocument) TableName() string {
+	return searchDocumentTable
+}
+
+// SearchQuery describes a search across the entities of the registry.
+type SearchQuery struct {
+	// Query is matched against names, display names, descriptions, schema property names and model names.
+	// Every whitespace separated term has to match. An empty query matches every entity.
+	Query string
+	// EntityTypes restricts the search to the given entity types.
+	// If empty, models, components, relationships and categories are searched.
+	EntityTypes []entity.EntityType
+	Limit       int //If 0 or unspecified then all results are returned and limit is not used
+	Offset      int
+}
+
+// SearchHit is a single search result. Hits are ordered by descending Score.
+type SearchHit struct {
+	EntityID    core.Uuid         `json:"entityId"`
+	EntityType  entity.EntityType `json:"entityType"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"displayName"`
+	Description string            `json:"description"`
+	ModelName   string            `json:"modelName"`
+	Category    string            `json:"category"`
+	Registrant  string            `json:"registrant"`
+	Score       float64           `json:"score"`
+}
+
+// SearchFacets counts all the matching entities (irrespective of Limit and Offset) by category, model and registrant.
+type SearchFacets struct {
+	Categories  map[string]int64 `json:"categories"`
+	Models      map[string]int64 `json:"models"`
+	Registrants map[string]int64 `json:"registrants"`
+}
+
+type SearchResult struct {
+	Hits   []SearchHit  `json:"hits"`
+	Total  int64        `json:"total"`
+	Facets SearchFacets `json:"facets"`
+}
+
+// setupSearch creates the FTS5 index over the search documents when the database is SQLite and FTS5 is available
+// (the SQLite driver has to be built with the `sqlite_fts5` build tag). Otherwise search falls back to LIKE matching.
+func (rm *RegistryManager) setupSearch() error {
+	var docs int64
+	if err := rm.db.Model(&searchDocument{}).Count(&docs).Error; err != nil {
+		return err
+	}
+	if docs == 0 {
+		// The registry might have been populated before search was available.
+		if err := rm.RebuildSearchIndex(); err != nil {
+			return err
+		}
+	}
+
+	if rm.db.Dialector.Name() != database.SQLITE {
+		return nil
+	}
+
+	var existing int64
+	if err := rm.db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", searchFTSTable).Scan(&existing).Error; err != nil {
+		return err
+	}
+	statements := []string{
+		fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %[1]s USING fts5(name, display_name, description, properties, model_name, content='%[2]s', content_rowid='id')`, searchFTSTable, searchDocumentTable),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_ai AFTER INSERT ON %[2]s BEGIN
+			INSERT INTO %[1]s(rowid, name, display_name, description, properties, model_name) VALUES (new.id, new.name, new.display_name, new.description, new.properties, new.model_name);
+		END`, searchFTSTable, searchDocumentTable),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_ad AFTER DELETE ON %[2]s BEGIN
+			INSERT INTO %[1]s(%[1]s, rowid, name, display_name, description, properties, model_name) VALUES ('delete', old.id, old.name, old.display_name, old.description, old.properties, old.model_name);
+		END`, searchFTSTable, searchDocumentTable),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[2]s_au AFTER UPDATE ON %[2]s BEGIN
+			INSERT INTO %[1]s(%[1]s, rowid, name, display_name, description, properties, model_name) VALUES ('delete', old.id, old.name, old.display_name, old.description, old.properties, old.model_name);
+			INSERT INTO %[1]s(rowid, name, display_name, description, properties, model_name) VALUES (new.id, new.name, new.display_name, new.description, new.properties, new.model_name);
+		END`, searchFTSTable, searchDocumentTable),
+	}
+	if err := rm.db.Session(&gorm.Session{Logger: gormlogger.Discard}).Exec(statements[0]).Error; err != nil {
+		// FTS5 is not compiled into the SQLite driver, use LIKE matching.
+		return nil
+	}
+	for _, stmt := range statements[1:] {
+		if err := rm.db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	if existing == 0 {
+		if err := rm.db.Exec(fmt.Sprintf(`INSERT INTO %[1]s(%[1]s) VALUES ('rebuild')`, searchFTSTable)).Error; err != nil {
+			return err
+		}
+	}
+	rm.fts = true
+	return nil
+}
+
+// RebuildSearchIndex recreates the search documents of every registered model, component, relationship and category.
+func (rm *RegistryManager) RebuildSearchIndex() error {
+	var models []model.ModelDefinition
+	if err := rm.db.Preload("Category").Preload("Registrant").Find(&models).Error; err != nil {
+		return ErrSearchRegistry(err)
+	}
+
+	docs := []searchDocument{}
+	for i := range models {
+		m := &models[i]
+		docs = append(docs, searchDocumentsFor(m.ID, m)...)
+
+		var components []component.ComponentDefinition
+		if err := rm.db.Where("model_id = ?", m.ID).Find(&components).Error; err != nil {
+			return ErrSearchRegistry(err)
+		}
+		for j := range components {
+			components[j].Model = m
+			docs = append(docs, searchDocumentsFor(components[j].ID, &components[j])...)
+		}
+
+		var relationships []relationship.RelationshipDefinition
+		if err := rm.db.Where("model_id = ?", m.ID).Find(&relationships).Error; err != nil {
+			return ErrSearchRegistry(err)
+		}
+		for j := range relationships {
+			docs = append(docs, searchDocumentsFor(relationships[j].ID, &relationships[j])...)
+		}
+	}
+
+	err := rm.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&searchDocument{}).Error; err != nil {
+			return err
+		}
+		return saveSearchDocuments(tx, docs)
+	})
+	if err != nil {
+		return ErrSearchRegistry(err)
+	}
+	return nil
+}
+
+// indexEntity creates or replaces the search documents of a registered entity.
+func (rm *RegistryManager) indexEntity(id core.Uuid, en entity.Entity) error {
+	docs := searchDocumentsFor(id, en)
+	for i := range docs {
+		// Categories are created along with models, look up their ID if the model does not carry it.
+		if docs[i].EntityType == entity.Category && docs[i].EntityID == (core.Uuid{}) {
+			var ids []core.Uuid
+			if err := rm.db.Model(&category.CategoryDefinition{}).Where("name = ?", docs[i].Name).Limit(1).Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			docs[i].EntityID = ids[0]
+		}
+	}
+	return rm.db.Transaction(func(tx *gorm.DB) error {
+		return saveSearchDocuments(tx, docs)
+	})
+}
+
+func saveSearchDocuments(tx *gorm.DB, docs []searchDocument) error {
+	seen := map[core.Uuid]struct{}{}
+	for _, doc := range docs {
+		if doc.EntityID == (core.Uuid{}) {
+			continue
+		}
+		if _, ok := seen[doc.EntityID]; ok {
+			continue
+		}
+		seen[doc.EntityID] = struct{}{}
+		if err := tx.Where("entity_id = ?", doc.EntityID).Delete(&searchDocument{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&doc).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func searchDocumentsFor(id core.Uuid, en entity.Entity) []searchDocument {
+	switch e := en.(type) {
+	case *model.ModelDefinition:
+		docs := []searchDocument{{
+			EntityID:    id,
+			EntityType:  entity.Model,
+			Name:        e.Name,
+			DisplayName: e.DisplayName,
+			Description: entityDescription(e),
+			ModelName:   e.Name,
+			Category:    e.Category.Name,
+			Registrant:  e.Registrant.Kind,
+		}}
+		if e.Category.Name != "" {
+			docs = append(docs, searchDocument{
+				EntityID:    e.Category.ID,
+				EntityType:  entity.Category,
+				Name:        e.Category.Name,
+				DisplayName: e.Category.Name,
+				Category:    e.Category.Name,
+			})
+		}
+		return docs
+	case *component.ComponentDefinition:
+		doc := searchDocument{
+			EntityID:    id,
+			EntityType:  entity.ComponentDefinition,
+			Name:        e.Component.Kind,
+			DisplayName: e.DisplayName,
+			Description: entityDescription(e),
+			Properties:  strings.Join(schemaPropertyNames(e.Component.Schema), " "),
+		}
+		if e.Model != nil {
+			doc.ModelName = e.Model.Name
+			doc.Category = e.Model.Category.Name
+			doc.Registrant = e.Model.Registrant.Kind
+		}
+		return []searchDocument{doc}
+	case *relationship.RelationshipDefinition:
+		return []searchDocument{{
+			EntityID:    id,
+			EntityType:  entity.RelationshipDefinition,
+			Name:        fmt.Sprintf("%s/%s/%s", e.Kind, e.RelationshipType, e.SubType),
+			Description: entityDescription(e),
+			ModelName:   e.Model.Name,
+		}}
+	}
+	return nil
+}
+
+// entityDescription reads the description of an entity, either top-level or inside its metadata.
+func entityDescription(en entity.Entity) string {
+	byt, err := json.Marshal(en)
+	if err != nil {
+		return ""
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(byt, &m); err != nil {
+		return ""
+	}
+	if description, ok := m["description"].(string); ok && description != "" {
+		return description
+	}
+	if metadata, ok := m["metadata"].(map[string]interface{}); ok {
+		description, _ := metadata["description"].(string)
+		return description
+	}
+	return ""
+}
+
+// schemaPropertyNames returns the sorted names of all the properties of a JSON schema, including nested ones.
+func schemaPropertyNames(schema string) []string {
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &m); err != nil {
+		return nil
+	}
+	set := map[string]struct{}{}
+	var walk func(s map[string]interface{})
+	walk = func(s map[string]interface{}) {
+		props, _ := s["properties"].(map[string]interface{})
+		for name, prop := range props {
+			set[name] = struct{}{}
+			if p, ok := prop.(map[string]interface{}); ok {
+				walk(p)
+			}
+		}
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			walk(items)
+		}
+	}
+	walk(m)
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// likeEscaper escapes the LIKE wildcards in the search terms, which are matched with `ESCAPE '\'`.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search finds the models, components, relationships and categories matching the query, ranked by relevance.
+// SQLite databases use the FTS5 index when available; other databases fall back to case-insensitive LIKE matching.
+func (rm *RegistryManager) Search(q SearchQuery) (SearchResult, error) {
+	result := SearchResult{
+		Hits: []SearchHit{},
+		Facets: SearchFacets{
+			Categories:  map[string]int64{},
+			Models:      map[string]int64{},
+			Registrants: map[string]int64{},
+		},
+	}
+
+	entityTypes := q.EntityTypes
+	if len(entityTypes) == 0 {
+		entityTypes = []entity.EntityType{entity.Model, entity.ComponentDefinition, entity.RelationshipDefinition, entity.Category}
+	}
+	terms := strings.Fields(strings.ToLower(q.Query))
+	useFTS := rm.fts && len(terms) > 0
+
+	// base returns the matching documents, without ranking.
+	base := func() *gorm.DB {
+		finder := rm.db.Table(searchDocumentTable).Where(searchDocumentTable+".entity_type IN ?", entityTypes)
+		if useFTS {
+			return finder.Where(fmt.Sprintf("%s.id IN (SELECT rowid FROM %[2]s WHERE %[2]s MATCH ?)", searchDocumentTable, searchFTSTable), ftsMatchQuery(terms))
+		}
+		for _, term := range terms {
+			like := "%" + likeEscaper.Replace(term) + "%"
+			finder = finder.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(display_name) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\' OR LOWER(properties) LIKE ? ESCAPE '\' OR LOWER(model_name) LIKE ? ESCAPE '\'`, like, like, like, like, like)
+		}
+		return finder
+	}
+
+	if err := base().Count(&result.Total).Error; err != nil {
+		return result, ErrSearchRegistry(err)
+	}
+
+	var finder *gorm.DB
+	switch {
+	case useFTS:
+		finder = rm.db.Table(searchDocumentTable).
+			Select(fmt.Sprintf("%s.*, -bm25(%[2]s, 10.0, 8.0, 1.0, 3.0, 2.0) AS score", searchDocumentTable, searchFTSTable)).
+			Joins(fmt.Sprintf("JOIN %[2]s ON %[2]s.rowid = %[1]s.id", searchDocumentTable, searchFTSTable)).
+			Where(searchFTSTable+" MATCH ?", ftsMatchQuery(terms)).
+			Where(searchDocumentTable+".entity_type IN ?", entityTypes)
+	case len(terms) > 0:
+		query := strings.Join(terms, " ")
+		like := likeEscaper.Replace(query)
+		finder = base().Select(`*, CASE
+			WHEN LOWER(name) = ? OR LOWER(display_name) = ? THEN 100
+			WHEN LOWER(name) LIKE ? ESCAPE '\' OR LOWER(display_name) LIKE ? ESCAPE '\' THEN 60
+			WHEN LOWER(name) LIKE ? ESCAPE '\' OR LOWER(display_name) LIKE ? ESCAPE '\' THEN 40
+			WHEN LOWER(properties) LIKE ? ESCAPE '\' THEN 20
+			ELSE 10 END AS score`,
+			query, query, like+"%", like+"%", "%"+like+"%", "%"+like+"%", "%"+like+"%")
+	default:
+		finder = base().Select("*, 0 AS score")
+	}
+	finder = finder.Order("score DESC").Order(searchDocumentTable + ".name").Offset(q.Offset)
+	if q.Limit != 0 {
+		finder = finder.Limit(q.Limit)
+	}
+
+	if err := finder.Scan(&result.Hits).Error; err != nil {
+		return result, ErrSearchRegistry(err)
+	}
+
+	facets := []struct {
+		column string
+		counts map[string]int64
+	}{
+		{"category", result.Facets.Categories},
+		{"model_name", result.Facets.Models},
+		{"registrant", result.Facets.Registrants},
+	}
+	for _, facet := range facets {
+		var rows []struct {
+			Value string
+			Count int64
+		}
+		err := base().
+			Select(fmt.Sprintf("%s AS value, COUNT(*) AS count", facet.column)).
+			Where(facet.column + " <> ''").
+			Group(facet.column).
+			Scan(&rows).Error
+		if err != nil {
+			return result, ErrSearchRegistry(err)
+		}
+		for _, row := range rows {
+			facet.counts[row.Value] = row.Count
+		}
+	}
+
+	return result, nil
+}
+
+// ftsMatchQuery quotes every term, so that user input cannot inject FTS5 query syntax, and matches terms by prefix.
+func ftsMatchQuery(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, `"`+strings.ReplaceAll(term, `"`, `""`)+`"*`)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/meshery/meshkit/database"
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	"github.com/meshery/schemas/models/v1beta1"
+	"github.com/meshery/schemas/models/v1beta1/category"
+	"github.com/meshery/schemas/models/v1beta1/model"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	connectionv1beta3 "github.com/meshery/schemas/models/v1beta3/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	db, err := database.New(database.Options{
+		Engine:   database.SQLITE,
+		Filename: ":memory:",
+	})
+	require.NoError(t, err)
+
+	rm, err := NewRegistryManager(&db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		rm.Cleanup()
+		assert.NoError(t, db.DBClose())
+	})
+
+	host := connectionv1beta3.Connection{Name: "github", Kind: "github"}
+	modelDef := model.ModelDefinition{
+		SchemaVersion: v1beta1.ModelSchemaVersion,
+		Version:       "1.0.0",
+		Name:          "kubernetes",
+		DisplayName:   "Kubernetes",
+		Status:        model.Enabled,
+		Category: category.CategoryDefinition{
+			Name: "Orchestration",
+		},
+		Model: model.Model{
+			Version: "1.0.0",
+		},
+	}
+	_, _, err = rm.RegisterEntity(host, &modelDef)
+	require.NoError(t, err)
+
+	for kind, schema := range map[string]string{
+		"Deployment": `{"type": "object", "properties": {"replicas": {"type": "integer"}}}`,
+		"Service":    `{"type": "object", "properties": {"ports": {"type": "array", "items": {"type": "object", "properties": {"targetPort": {"type": "integer"}}}}}}`,
+	} {
+		byt, err := json.Marshal(map[string]interface{}{
+			"displayName": kind,
+			"component":   map[string]interface{}{"kind": kind, "version": "v1", "schema": schema},
+		})
+		require.NoError(t, err)
+		var comp component.ComponentDefinition
+		require.NoError(t, json.Unmarshal(byt, &comp))
+		comp.Model = &modelDef
+		_, _, err = rm.RegisterEntity(host, &comp)
+		require.NoError(t, err)
+	}
+
+	result, err := rm.Search(SearchQuery{Query: "deploy"})
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, int64(1), result.Total)
+	assert.Equal(t, "Deployment", result.Hits[0].Name)
+	assert.Equal(t, entity.ComponentDefinition, result.Hits[0].EntityType)
+	assert.Equal(t, map[string]int64{"kubernetes": 1}, result.Facets.Models)
+	assert.Equal(t, map[string]int64{"Orchestration": 1}, result.Facets.Categories)
+
+	// Schema property names are searchable.
+	result, err = rm.Search(SearchQuery{Query: "targetPort"})
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "Service", result.Hits[0].Name)
+
+	result, err = rm.Search(SearchQuery{EntityTypes: []entity.EntityType{entity.Category}})
+	require.NoError(t, err)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, "Orchestration", result.Hits[0].Name)
+
+	result, err = rm.Search(SearchQuery{Query: "kubernetes", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Total)
+	require.Len(t, result.Hits, 1)
+	assert.Equal(t, entity.Model, result.Hits[0].EntityType, "exact name matches rank first")
+
+	// The index is recovered from the registered entities.
+	require.NoError(t, rm.RebuildSearchIndex())
+	result, err = rm.Search(SearchQuery{Query: "kubernetes"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Total)
+
+	// Without FTS5, the LIKE wildcards of the query are matched literally.
+	rm.fts = false
+	result, err = rm.Search(SearchQuery{Query: "deploy"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	for _, query := range []string{"deplo_ment", "deploy%", `deploy\`} {
+		result, err = rm.Search(SearchQuery{Query: query})
+		require.NoError(t, err)
+		assert.Zero(t, result.Total, query)
+	}
+}
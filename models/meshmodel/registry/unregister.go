@@ -0,0 +1,240 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	core "github.com/meshery/schemas/models/core"
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	connectionv1beta1 "github.com/meshery/schemas/models/v1beta1/connection"
+	"github.com/meshery/schemas/models/v1beta1/model"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	connectionv1beta3 "github.com/meshery/schemas/models/v1beta3/connection"
+	"gorm.io/gorm"
+)
+
+// UnregisterOptions configures UnregisterModel and UnregisterRegistrant.
+type UnregisterOptions struct {
+	// When DryRun is true nothing is deleted, the returned report lists what would have been deleted.
+	DryRun bool
+	// SVGBaseDir is the directory the SVGs of the models were written to during registration.
+	// When empty, SVG files are left untouched.
+	SVGBaseDir string
+}
+
+// UnregisterReport lists the registry entries removed by (or, in dry-run mode, that would be removed by) an unregister call.
+type UnregisterReport struct {
+	DryRun        bool
+	Registrants   []core.Uuid
+	Models        []core.Uuid
+	Components    []core.Uuid
+	Relationships []core.Uuid
+	Connections   []core.Uuid // connection definitions
+	Registries    int64       // number of rows in the `registries` table
+	SVGPaths      []string    // SVG directories inside SVGBaseDir
+	// SVGErrors lists the SVG directories which could not be removed. The registry entries are removed regardless,
+	// so these failures do not make the unregister call fail.
+	SVGErrors []error
+}
+
+// UnregisterModel removes every registered model with the given name and version, along with their components,
+// relationships, connection definitions, `registries` entries and SVGs.
+// If version is empty, all versions of the model are removed.
+// The SVG directory of a model is shared by all of its versions, hence it is only removed with the last version of the model.
+func (rm *RegistryManager) UnregisterModel(name, version string, opts UnregisterOptions) (UnregisterReport, error) {
+	query := rm.db.Model(&model.ModelDefinition{}).Where("model_dbs.name = ?", name)
+	if version != "" {
+		query = query.Where("model_dbs.model->>'version' = ?", version)
+	}
+	var models []model.ModelDefinition
+	if err := query.Find(&models).Error; err != nil {
+		return UnregisterReport{DryRun: opts.DryRun}, ErrUnregisterModel(err, name, version)
+	}
+
+	report, err := rm.unregister(models, nil, opts)
+	if err != nil {
+		return report, ErrUnregisterModel(err, name, version)
+	}
+	return report, nil
+}
+
+// UnregisterRegistrant removes the registrant with the given ID and cascades to every entity registered through it,
+// including the components, relationships and connection definitions of its models.
+func (rm *RegistryManager) UnregisterRegistrant(id string, opts UnregisterOptions) (UnregisterReport, error) {
+	registrantID, err := uuid.FromString(id)
+	if err != nil {
+		return UnregisterReport{DryRun: opts.DryRun}, ErrUnregisterRegistrant(err, id)
+	}
+
+	var models []model.ModelDefinition
+	err = rm.db.Model(&model.ModelDefinition{}).
+		Joins("JOIN registries ON registries.entity = model_dbs.id").
+		Where("registries.registrant_id = ? AND registries.type = ?", registrantID, entity.Model).
+		Find(&models).Error
+	if err != nil {
+		return UnregisterReport{DryRun: opts.DryRun}, ErrUnregisterRegistrant(err, id)
+	}
+
+	report, err := rm.unregister(models, []core.Uuid{registrantID}, opts)
+	if err != nil {
+		return report, ErrUnregisterRegistrant(err, id)
+	}
+	return report, nil
+}
+
+// unregister collects the entities belonging to the given models and registrants and deletes them in a single transaction.
+func (rm *RegistryManager) unregister(models []model.ModelDefinition, registrantIDs []core.Uuid, opts UnregisterOptions) (UnregisterReport, error) {
+	report := UnregisterReport{DryRun: opts.DryRun, Registrants: registrantIDs}
+
+	err := rm.Transaction(func(txm *RegistryManager) error {
+		db := txm.db
+
+		// Entities registered directly through the registrants, irrespective of their model.
+		if len(registrantIDs) > 0 {
+			var entries []Registry
+			if err := db.Where("registrant_id IN ?", registrantIDs).Find(&entries).Error; err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				switch entry.Type {
+				case entity.ComponentDefinition:
+					report.Components = appendUnique(report.Components, entry.Entity)
+				case entity.RelationshipDefinition:
+					report.Relationships = appendUnique(report.Relationships, entry.Entity)
+				case entity.ConnectionDefinition:
+					report.Connections = appendUnique(report.Connections, entry.Entity)
+				}
+			}
+		}
+
+		modelNames := []string{}
+		for _, m := range models {
+			report.Models = appendUnique(report.Models, m.ID)
+			if !slices.Contains(modelNames, m.Name) {
+				modelNames = append(modelNames, m.Name)
+			}
+
+			var connectionIDs []core.Uuid
+			if err := db.Model(&connectionv1beta3.ConnectionDefinition{}).
+				Where("connection_definition_dbs.model_reference->>'name' = ?", m.Name).
+				Where("connection_definition_dbs.model_reference->'model'->>'version' = ?", m.Model.Version).
+				Pluck("id", &connectionIDs).Error; err != nil {
+				return err
+			}
+			report.Connections = appendUnique(report.Connections, connectionIDs...)
+		}
+
+		if len(report.Models) > 0 {
+			var componentIDs, relationshipIDs []core.Uuid
+			if err := db.Model(&component.ComponentDefinition{}).
+				Where("model_id IN ?", report.Models).
+				Pluck("id", &componentIDs).Error; err != nil {
+				return err
+			}
+			if err := db.Model(&relationship.RelationshipDefinition{}).
+				Where("model_id IN ?", report.Models).
+				Pluck("id", &relationshipIDs).Error; err != nil {
+				return err
+			}
+			report.Components = appendUnique(report.Components, componentIDs...)
+			report.Relationships = appendUnique(report.Relationships, relationshipIDs...)
+		}
+
+		entityIDs := slices.Concat(report.Models, report.Components, report.Relationships, report.Connections)
+		if len(entityIDs) == 0 && len(registrantIDs) == 0 {
+			return nil
+		}
+		registries := func() *gorm.DB {
+			query := db.Model(&Registry{})
+			switch {
+			case len(entityIDs) > 0 && len(registrantIDs) > 0:
+				return query.Where("entity IN ? OR registrant_id IN ?", entityIDs, registrantIDs)
+			case len(entityIDs) > 0:
+				return query.Where("entity IN ?", entityIDs)
+			default:
+				return query.Where("registrant_id IN ?", registrantIDs)
+			}
+		}
+		if err := registries().Count(&report.Registries).Error; err != nil {
+			return err
+		}
+
+		// SVGs are written per model name, they can only be removed once no version of the model is left.
+		if opts.SVGBaseDir != "" {
+			for _, name := range modelNames {
+				var remaining int64
+				if err := db.Model(&model.ModelDefinition{}).
+					Where("name = ? AND id NOT IN ?", name, report.Models).
+					Count(&remaining).Error; err != nil {
+					return err
+				}
+				svgPath := filepath.Join(opts.SVGBaseDir, name)
+				// Never remove anything outside of a model's own directory.
+				if rel, err := filepath.Rel(opts.SVGBaseDir, svgPath); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+					continue
+				}
+				if _, err := os.Stat(svgPath); remaining == 0 && err == nil {
+					report.SVGPaths = append(report.SVGPaths, svgPath)
+				}
+			}
+		}
+
+		if opts.DryRun {
+			return nil
+		}
+
+		deletions := []struct {
+			ids   []core.Uuid
+			model interface{}
+		}{
+			{report.Components, &component.ComponentDefinition{}},
+			{report.Relationships, &relationship.RelationshipDefinition{}},
+			{report.Connections, &connectionv1beta3.ConnectionDefinition{}},
+			{report.Models, &model.ModelDefinition{}},
+			{registrantIDs, &connectionv1beta1.Connection{}},
+		}
+		for _, d := range deletions {
+			if len(d.ids) == 0 {
+				continue
+			}
+			if err := db.Unscoped().Where("id IN ?", d.ids).Delete(d.model).Error; err != nil {
+				return err
+			}
+		}
+		for _, ids := range [][]core.Uuid{report.Components, report.Relationships, report.Models} {
+			if len(ids) == 0 {
+				continue
+			}
+			if err := db.Where("entity_id IN ?", ids).Delete(&searchDocument{}).Error; err != nil {
+				return err
+			}
+		}
+		return registries().Unscoped().Delete(&Registry{}).Error
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if !opts.DryRun {
+		rm.onCommit(func(rm *RegistryManager) { rm.caches.invalidate(entity.Model) })
+		for _, svgPath := range report.SVGPaths {
+			if err := os.RemoveAll(svgPath); err != nil {
+				report.SVGErrors = append(report.SVGErrors, err)
+			}
+		}
+	}
+	return report, nil
+}
+
+func appendUnique(ids []core.Uuid, more ...core.Uuid) []core.Uuid {
+	for _, id := range more {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
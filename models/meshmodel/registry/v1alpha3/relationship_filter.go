@@ -1,17 +1,19 @@
 package v1alpha3
 
 import (
+	"encoding/json"
+
 	"github.com/meshery/meshkit/database"
 	"github.com/meshery/meshkit/models/meshmodel/entity"
 	"github.com/meshery/meshkit/models/meshmodel/registry"
 	"github.com/meshery/schemas/models/v1alpha3/relationship"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
-// For now, only filtering by Kind and SubType are allowed.
-// In the future, we will add support to query using `selectors` (using CUE)
-// TODO: Add support for Model
+// RelationshipFilter filters relationships by their kind, type and subType, by the name and version of their model,
+// and by the components selected by their `selectors` (see From and To).
 type RelationshipFilter struct {
 	Id               string
 	Kind             string
@@ -25,6 +27,69 @@ type RelationshipFilter struct {
 	Limit            int    //If 0 or  unspecified then all records are returned and limit is not used
 	Offset           int
 	Status           string
+	// From and To match the relationships whose allow selectors select the given components on the from and to side.
+	// When both are set, they have to be matched by the same selector, i.e. the relationship connects From to To.
+	// Components denied by the selector are not matched.
+	From SelectorFilter
+	To   SelectorFilter
+}
+
+// SelectorFilter matches the components selected by a relationship selector.
+// Empty fields match any value, as do wildcard (`*`) or missing fields of the selector.
+type SelectorFilter struct {
+	Kind      string
+	ModelName string
+}
+
+func (sf SelectorFilter) isEmpty() bool {
+	return sf.Kind == "" && sf.ModelName == ""
+}
+
+// matches reports whether any of the selector items (the `from` or `to` list of a selector) selects the component.
+func (sf SelectorFilter) matches(items []interface{}) bool {
+	for _, item := range items {
+		i, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := i["kind"].(string)
+		modelName := ""
+		if m, ok := i["model"].(map[string]interface{}); ok {
+			modelName, _ = m["name"].(string)
+		}
+		if selectorValueMatches(kind, sf.Kind) && selectorValueMatches(modelName, sf.ModelName) {
+			return true
+		}
+	}
+	return false
+}
+
+func selectorValueMatches(selectorValue, filterValue string) bool {
+	return filterValue == "" || selectorValue == "" || selectorValue == "*" || selectorValue == filterValue
+}
+
+// matchesSelectors reports whether a selector of the relationship allows (and does not deny) the From and To components.
+func (relationshipFilter *RelationshipFilter) matchesSelectors(rd *relationship.RelationshipDefinition) bool {
+	// Selectors are matched on their JSON representation, which is the representation selectors are authored in.
+	byt, err := json.Marshal(rd.Selectors)
+	if err != nil {
+		return false
+	}
+	var selectors []map[string]map[string][]interface{}
+	if err := json.Unmarshal(byt, &selectors); err != nil {
+		return false
+	}
+	for _, selector := range selectors {
+		allow, deny := selector["allow"], selector["deny"]
+		if !relationshipFilter.From.isEmpty() && (!relationshipFilter.From.matches(allow["from"]) || relationshipFilter.From.matches(deny["from"])) {
+			continue
+		}
+		if !relationshipFilter.To.isEmpty() && (!relationshipFilter.To.matches(allow["to"]) || relationshipFilter.To.matches(deny["to"])) {
+			continue
+		}
+		return true
+	}
+	return false
 }
 
 // Create the filter from map[string]interface{}
@@ -90,6 +155,10 @@ func (relationshipFilter *RelationshipFilter) Get(db *database.Handler) ([]entit
 		}
 	}
 
+	if !relationshipFilter.From.isEmpty() || !relationshipFilter.To.isEmpty() {
+		return relationshipFilter.getBySelectors(finder)
+	}
+
 	var count int64
 	finder.Count(&count)
 
@@ -113,3 +182,29 @@ func (relationshipFilter *RelationshipFilter) Get(db *database.Handler) ([]entit
 	// Should have count unique relationships (by model version, model name, and relationship's kind, type, subtype, version)
 	return defs, count, int(count), nil
 }
+
+// getBySelectors matches the selectors of the relationships found by finder.
+// Selectors are stored as JSON and their matching rules are not expressible in SQL portably,
+// so they are matched in memory and Offset and Limit are applied afterwards.
+func (relationshipFilter *RelationshipFilter) getBySelectors(finder *gorm.DB) ([]entity.Entity, int64, int, error) {
+	var relationshipDefinitionsWithModel []relationship.RelationshipDefinition
+	if err := finder.Find(&relationshipDefinitionsWithModel).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	defs := make([]entity.Entity, 0, len(relationshipDefinitionsWithModel))
+	for _, rd := range relationshipDefinitionsWithModel {
+		_rd := rd
+		if relationshipFilter.matchesSelectors(&_rd) {
+			defs = append(defs, &_rd)
+		}
+	}
+	count := int64(len(defs))
+
+	start := min(relationshipFilter.Offset, len(defs))
+	end := len(defs)
+	if relationshipFilter.Limit != 0 {
+		end = min(start+relationshipFilter.Limit, end)
+	}
+	return defs[start:end], count, int(count), nil
+}
@@ -0,0 +1,49 @@
+package v1alpha3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationshipFilterMatchesSelectors(t *testing.T) {
+	var rd relationship.RelationshipDefinition
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"kind": "edge",
+		"type": "non-binding",
+		"subType": "network",
+		"selectors": [{
+			"allow": {
+				"from": [{"kind": "Service", "model": {"name": "kubernetes"}}],
+				"to": [{"kind": "*", "model": {"name": "kubernetes"}}]
+			},
+			"deny": {
+				"from": [],
+				"to": [{"kind": "Namespace", "model": {"name": "kubernetes"}}]
+			}
+		}]
+	}`), &rd))
+
+	tests := []struct {
+		name   string
+		filter RelationshipFilter
+		want   bool
+	}{
+		{"from kind", RelationshipFilter{From: SelectorFilter{Kind: "Service"}}, true},
+		{"from kind and model", RelationshipFilter{From: SelectorFilter{Kind: "Service", ModelName: "kubernetes"}}, true},
+		{"from other kind", RelationshipFilter{From: SelectorFilter{Kind: "Pod"}}, false},
+		{"from other model", RelationshipFilter{From: SelectorFilter{Kind: "Service", ModelName: "istio"}}, false},
+		{"to wildcard kind", RelationshipFilter{To: SelectorFilter{Kind: "Deployment", ModelName: "kubernetes"}}, true},
+		{"to denied kind", RelationshipFilter{To: SelectorFilter{Kind: "Namespace"}}, false},
+		{"from and to", RelationshipFilter{From: SelectorFilter{Kind: "Service"}, To: SelectorFilter{Kind: "Pod"}}, true},
+		{"from and denied to", RelationshipFilter{From: SelectorFilter{Kind: "Service"}, To: SelectorFilter{Kind: "Namespace"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.matchesSelectors(&rd))
+		})
+	}
+}
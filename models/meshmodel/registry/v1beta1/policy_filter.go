@@ -39,7 +39,8 @@ func (pf *PolicyFilter) Get(db *database.Handler) ([]entity.Entity, int64, int,
 	var policyDefinitionWithModel []v1beta1.PolicyDefinition
 	finder := db.Model(&v1beta1.PolicyDefinition{}).
 		Select("policy_definition_dbs.*").
-		Joins("JOIN model_dbs ON model_dbs.id = policy_definition_dbs.model_id")
+		// PolicyDefinition.ModelID is stored in the `modelID` column (see its gorm tag), it has to be quoted to keep its case.
+		Joins(`JOIN model_dbs ON model_dbs.id = policy_definition_dbs."modelID"`)
 	if pf.Kind != "" {
 		finder = finder.Where("policy_definition_dbs.kind = ?", pf.Kind)
 	}
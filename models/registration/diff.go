@@ -0,0 +1,333 @@
+package registration
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	"github.com/meshery/schemas/models/v1beta3/component"
+)
+
+type PropertyChangeType string
+
+const (
+	PropertyAdded           PropertyChangeType = "added"
+	PropertyRemoved         PropertyChangeType = "removed"
+	PropertyTypeChanged     PropertyChangeType = "typeChanged"
+	PropertyRequiredAdded   PropertyChangeType = "requiredAdded"
+	PropertyRequiredRemoved PropertyChangeType = "requiredRemoved"
+)
+
+// PropertyChange describes the change of a single property in the schema of a component.
+type PropertyChange struct {
+	// Path of the property inside the schema, e.g. `spec.template.spec.containers[].image`.
+	Path     string             `json:"path"`
+	Change   PropertyChangeType `json:"change"`
+	OldType  string             `json:"oldType,omitempty"`
+	NewType  string             `json:"newType,omitempty"`
+	Breaking bool               `json:"breaking"`
+}
+
+// ComponentDiff lists the schema changes of a component present in both PackagingUnits.
+// Kind identifies the component as `kind@apiVersion`, see DiffReport.
+type ComponentDiff struct {
+	Kind       string           `json:"kind"`
+	Properties []PropertyChange `json:"properties"`
+	Breaking   bool             `json:"breaking"`
+}
+
+// ComponentRename pairs a removed component with an added component having the same schema.
+type ComponentRename struct {
+	OldKind string `json:"oldKind"`
+	NewKind string `json:"newKind"`
+}
+
+// DiffReport describes the changes between two versions of a model.
+// Components are identified by `kind@apiVersion`, or by their kind alone when they have no API version, so that
+// the versions of a kind (e.g. `HorizontalPodAutoscaler@autoscaling/v1` and `@autoscaling/v2`) are compared separately.
+// Relationships are identified by `kind/type/subType`. Several relationships may share it, in which case the
+// identifier is listed once for each of them.
+type DiffReport struct {
+	ModelName            string            `json:"modelName"`
+	OldVersion           string            `json:"oldVersion"`
+	NewVersion           string            `json:"newVersion"`
+	AddedComponents      []string          `json:"addedComponents"`
+	RemovedComponents    []string          `json:"removedComponents"`
+	RenamedComponents    []ComponentRename `json:"renamedComponents"`
+	ChangedComponents    []ComponentDiff   `json:"changedComponents"`
+	AddedRelationships   []string          `json:"addedRelationships"`
+	RemovedRelationships []string          `json:"removedRelationships"`
+	ChangedRelationships []string          `json:"changedRelationships"`
+	// Breaking is true when upgrading to the new version can invalidate existing designs,
+	// i.e. when components or relationships are removed or renamed, or a component schema changed in an incompatible way.
+	Breaking bool `json:"breaking"`
+}
+
+/*
+Diff compares two versions of a model and reports the components and relationships which were added, removed or changed.
+Components are matched by kind and API version and compared using their JSON schema; a removed and an added component having the same schema are reported as renamed.
+Relationships are matched by kind, type and subType and compared using their selectors.
+`oldPkg` is usually built from the registry using `GetRegisteredPkgUnit` and `newPkg` is the PackagingUnit being registered.
+*/
+func Diff(oldPkg, newPkg PackagingUnit) DiffReport {
+	report := DiffReport{
+		ModelName:  newPkg.Model.Name,
+		OldVersion: oldPkg.Model.Model.Version,
+		NewVersion: newPkg.Model.Model.Version,
+	}
+
+	oldComponents := componentsByKey(oldPkg.Components)
+	newComponents := componentsByKey(newPkg.Components)
+
+	var removed, added []string
+	for kind := range oldComponents {
+		if _, ok := newComponents[kind]; !ok {
+			removed = append(removed, kind)
+		}
+	}
+	for kind := range newComponents {
+		if _, ok := oldComponents[kind]; !ok {
+			added = append(added, kind)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+
+	for _, oldKind := range removed {
+		oldSchema := parseSchema(oldComponents[oldKind].Component.Schema)
+		if len(oldSchema) == 0 {
+			report.RemovedComponents = append(report.RemovedComponents, oldKind)
+			continue
+		}
+		renamed := false
+		for i, newKind := range added {
+			if reflect.DeepEqual(oldSchema, parseSchema(newComponents[newKind].Component.Schema)) {
+				report.RenamedComponents = append(report.RenamedComponents, ComponentRename{OldKind: oldKind, NewKind: newKind})
+				added = slices.Delete(added, i, i+1)
+				renamed = true
+				break
+			}
+		}
+		if !renamed {
+			report.RemovedComponents = append(report.RemovedComponents, oldKind)
+		}
+	}
+	report.AddedComponents = added
+
+	kinds := make([]string, 0, len(oldComponents))
+	for kind := range oldComponents {
+		if _, ok := newComponents[kind]; ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		changes := diffSchema(parseSchema(oldComponents[kind].Component.Schema), parseSchema(newComponents[kind].Component.Schema))
+		if len(changes) == 0 {
+			continue
+		}
+		cd := ComponentDiff{Kind: kind, Properties: changes}
+		for _, change := range changes {
+			cd.Breaking = cd.Breaking || change.Breaking
+		}
+		report.ChangedComponents = append(report.ChangedComponents, cd)
+	}
+
+	oldRelationships := relationshipsByKey(oldPkg.Relationships)
+	newRelationships := relationshipsByKey(newPkg.Relationships)
+	for key, oldRels := range oldRelationships {
+		removed, added := unmatchedRelationships(oldRels, newRelationships[key])
+		// Relationships sharing a key are told apart by their selectors only, so the unmatched ones are paired as changed.
+		changed := min(removed, added)
+		for i := 0; i < changed; i++ {
+			report.ChangedRelationships = append(report.ChangedRelationships, key)
+		}
+		for i := changed; i < removed; i++ {
+			report.RemovedRelationships = append(report.RemovedRelationships, key)
+		}
+		for i := changed; i < added; i++ {
+			report.AddedRelationships = append(report.AddedRelationships, key)
+		}
+	}
+	for key, newRels := range newRelationships {
+		if _, ok := oldRelationships[key]; !ok {
+			for range newRels {
+				report.AddedRelationships = append(report.AddedRelationships, key)
+			}
+		}
+	}
+	sort.Strings(report.RemovedRelationships)
+	sort.Strings(report.ChangedRelationships)
+	sort.Strings(report.AddedRelationships)
+
+	report.Breaking = len(report.RemovedComponents) > 0 || len(report.RenamedComponents) > 0 || len(report.RemovedRelationships) > 0
+	for _, cd := range report.ChangedComponents {
+		report.Breaking = report.Breaking || cd.Breaking
+	}
+	return report
+}
+
+func componentsByKey(components []component.ComponentDefinition) map[string]component.ComponentDefinition {
+	m := make(map[string]component.ComponentDefinition, len(components))
+	for _, comp := range components {
+		m[componentKey(comp)] = comp
+	}
+	return m
+}
+
+// componentKey identifies a component by its kind and API version.
+func componentKey(comp component.ComponentDefinition) string {
+	if comp.Component.Version == "" {
+		return comp.Component.Kind
+	}
+	return comp.Component.Kind + "@" + comp.Component.Version
+}
+
+func relationshipsByKey(relationships []relationship.RelationshipDefinition) map[string][]relationship.RelationshipDefinition {
+	m := make(map[string][]relationship.RelationshipDefinition, len(relationships))
+	for _, rel := range relationships {
+		key := fmt.Sprintf("%s/%s/%s", rel.Kind, rel.RelationshipType, rel.SubType)
+		m[key] = append(m[key], rel)
+	}
+	return m
+}
+
+// unmatchedRelationships compares the selectors of the relationships as multisets, returning the number of old
+// relationships without an identical new one and the number of new relationships without an identical old one.
+func unmatchedRelationships(oldRels, newRels []relationship.RelationshipDefinition) (removed, added int) {
+	newSelectors := make([]interface{}, 0, len(newRels))
+	for _, rel := range newRels {
+		newSelectors = append(newSelectors, toGeneric(rel.Selectors))
+	}
+	for _, rel := range oldRels {
+		selectors := toGeneric(rel.Selectors)
+		i := slices.IndexFunc(newSelectors, func(s interface{}) bool { return reflect.DeepEqual(s, selectors) })
+		if i < 0 {
+			removed++
+			continue
+		}
+		newSelectors = slices.Delete(newSelectors, i, i+1)
+	}
+	return removed, len(newSelectors)
+}
+
+// toGeneric converts v to its JSON representation (maps, slices and primitives) so that it can be compared structurally.
+func toGeneric(v interface{}) interface{} {
+	byt, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	_ = json.Unmarshal(byt, &generic)
+	return generic
+}
+
+func parseSchema(schema string) map[string]interface{} {
+	m := map[string]interface{}{}
+	if schema == "" {
+		return m
+	}
+	_ = json.Unmarshal([]byte(schema), &m)
+	return m
+}
+
+// diffSchema compares the properties of two JSON schemas, recursing into nested objects and array items.
+func diffSchema(oldSchema, newSchema map[string]interface{}) []PropertyChange {
+	changes := []PropertyChange{}
+	diffProperties("", oldSchema, newSchema, &changes)
+	return changes
+}
+
+func diffProperties(prefix string, oldSchema, newSchema map[string]interface{}, changes *[]PropertyChange) {
+	oldProps, _ := oldSchema["properties"].(map[string]interface{})
+	newProps, _ := newSchema["properties"].(map[string]interface{})
+	oldRequired := requiredSet(oldSchema)
+	newRequired := requiredSet(newSchema)
+
+	names := make([]string, 0, len(oldProps)+len(newProps))
+	for name := range oldProps {
+		names = append(names, name)
+	}
+	for name := range newProps {
+		if _, ok := oldProps[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		oldProp, inOld := oldProps[name].(map[string]interface{})
+		newProp, inNew := newProps[name].(map[string]interface{})
+
+		switch {
+		case inOld && !inNew:
+			*changes = append(*changes, PropertyChange{Path: path, Change: PropertyRemoved, OldType: schemaType(oldProp), Breaking: oldRequired[name]})
+			continue
+		case !inOld && inNew:
+			*changes = append(*changes, PropertyChange{Path: path, Change: PropertyAdded, NewType: schemaType(newProp), Breaking: newRequired[name]})
+			continue
+		case !inOld && !inNew:
+			continue
+		}
+
+		oldType, newType := schemaType(oldProp), schemaType(newProp)
+		if oldType != newType {
+			*changes = append(*changes, PropertyChange{Path: path, Change: PropertyTypeChanged, OldType: oldType, NewType: newType, Breaking: true})
+			continue
+		}
+		if !oldRequired[name] && newRequired[name] {
+			*changes = append(*changes, PropertyChange{Path: path, Change: PropertyRequiredAdded, Breaking: true})
+		} else if oldRequired[name] && !newRequired[name] {
+			*changes = append(*changes, PropertyChange{Path: path, Change: PropertyRequiredRemoved})
+		}
+
+		diffProperties(path, oldProp, newProp, changes)
+		oldItems, okOld := oldProp["items"].(map[string]interface{})
+		newItems, okNew := newProp["items"].(map[string]interface{})
+		if okOld && okNew {
+			itemsPath := path + "[]"
+			if oldItemsType, newItemsType := schemaType(oldItems), schemaType(newItems); oldItemsType != newItemsType {
+				*changes = append(*changes, PropertyChange{Path: itemsPath, Change: PropertyTypeChanged, OldType: oldItemsType, NewType: newItemsType, Breaking: true})
+				continue
+			}
+			diffProperties(itemsPath, oldItems, newItems, changes)
+		}
+	}
+}
+
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	set := map[string]bool{}
+	required, _ := schema["required"].([]interface{})
+	for _, r := range required {
+		if name, ok := r.(string); ok {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// schemaType returns the `type` of a JSON schema, or its `$ref` when the type is not given inline.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			types = append(types, fmt.Sprint(v))
+		}
+		sort.Strings(types)
+		return fmt.Sprint(types)
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		return ref
+	}
+	return ""
+}
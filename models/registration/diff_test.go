@@ -0,0 +1,137 @@
+package registration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPkgUnit(t *testing.T, version string, components map[string]string, relationships ...string) PackagingUnit {
+	t.Helper()
+
+	pkg := PackagingUnit{}
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "test-model", "model": {"version": "`+version+`"}}`), &pkg.Model))
+	for kind, schema := range components {
+		pkg.Components = append(pkg.Components, testComponent(t, kind, "v1", schema))
+	}
+	for _, rel := range relationships {
+		var relDef relationship.RelationshipDefinition
+		require.NoError(t, json.Unmarshal([]byte(rel), &relDef))
+		pkg.Relationships = append(pkg.Relationships, relDef)
+	}
+	return pkg
+}
+
+func testComponent(t *testing.T, kind, version, schema string) component.ComponentDefinition {
+	t.Helper()
+
+	comp := map[string]interface{}{
+		"component": map[string]interface{}{"kind": kind, "version": version, "schema": schema},
+	}
+	byt, err := json.Marshal(comp)
+	require.NoError(t, err)
+	var compDef component.ComponentDefinition
+	require.NoError(t, json.Unmarshal(byt, &compDef))
+	return compDef
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	oldPkg := testPkgUnit(t, "v1.0.0", map[string]string{
+		"Deployment": `{"type": "object", "required": ["replicas"], "properties": {"replicas": {"type": "integer"}, "paused": {"type": "boolean"}}}`,
+		"Service":    `{"type": "object", "properties": {"ports": {"type": "array", "items": {"type": "object", "properties": {"port": {"type": "integer"}}}}}}`,
+		"OldPolicy":  `{"type": "object", "properties": {"rules": {"type": "array"}}}`,
+		"Secret":     `{"type": "object", "properties": {"data": {"type": "object"}}}`,
+	}, `{"kind": "edge", "type": "binding", "subType": "firewall"}`)
+
+	newPkg := testPkgUnit(t, "v1.1.0", map[string]string{
+		"Deployment": `{"type": "object", "required": ["replicas"], "properties": {"replicas": {"type": "string"}, "strategy": {"type": "object"}}}`,
+		"Service":    `{"type": "object", "properties": {"ports": {"type": "array", "items": {"type": "object", "properties": {"port": {"type": "integer"}, "name": {"type": "string"}}}}}}`,
+		"NewPolicy":  `{"type": "object", "properties": {"rules": {"type": "array"}}}`,
+		"ConfigMap":  `{"type": "object"}`,
+	}, `{"kind": "hierarchical", "type": "parent", "subType": "inventory"}`)
+
+	report := Diff(oldPkg, newPkg)
+
+	assert.Equal(t, "v1.0.0", report.OldVersion)
+	assert.Equal(t, "v1.1.0", report.NewVersion)
+	assert.Equal(t, []string{"ConfigMap@v1"}, report.AddedComponents)
+	assert.Equal(t, []string{"Secret@v1"}, report.RemovedComponents)
+	assert.Equal(t, []ComponentRename{{OldKind: "OldPolicy@v1", NewKind: "NewPolicy@v1"}}, report.RenamedComponents)
+	assert.Equal(t, []string{"hierarchical/parent/inventory"}, report.AddedRelationships)
+	assert.Equal(t, []string{"edge/binding/firewall"}, report.RemovedRelationships)
+	assert.True(t, report.Breaking)
+
+	require.Len(t, report.ChangedComponents, 2)
+	deployment := report.ChangedComponents[0]
+	assert.Equal(t, "Deployment@v1", deployment.Kind)
+	assert.True(t, deployment.Breaking)
+	assert.Equal(t, []PropertyChange{
+		{Path: "paused", Change: PropertyRemoved, OldType: "boolean"},
+		{Path: "replicas", Change: PropertyTypeChanged, OldType: "integer", NewType: "string", Breaking: true},
+		{Path: "strategy", Change: PropertyAdded, NewType: "object"},
+	}, deployment.Properties)
+
+	service := report.ChangedComponents[1]
+	assert.Equal(t, "Service@v1", service.Kind)
+	assert.False(t, service.Breaking)
+	assert.Equal(t, []PropertyChange{
+		{Path: "ports[].name", Change: PropertyAdded, NewType: "string"},
+	}, service.Properties)
+}
+
+func TestDiffNonBreaking(t *testing.T) {
+	t.Parallel()
+
+	schema := `{"type": "object", "required": ["spec"], "properties": {"spec": {"type": "object"}}}`
+	oldPkg := testPkgUnit(t, "v1.0.0", map[string]string{"Pod": schema})
+	newPkg := testPkgUnit(t, "v1.0.1", map[string]string{
+		"Pod": `{"type": "object", "properties": {"spec": {"type": "object"}}}`,
+		"Job": schema,
+	})
+
+	report := Diff(oldPkg, newPkg)
+
+	assert.False(t, report.Breaking)
+	assert.Equal(t, []string{"Job@v1"}, report.AddedComponents)
+	require.Len(t, report.ChangedComponents, 1)
+	assert.Equal(t, []PropertyChange{{Path: "spec", Change: PropertyRequiredRemoved}}, report.ChangedComponents[0].Properties)
+}
+
+func TestDiffSharedKeys(t *testing.T) {
+	t.Parallel()
+
+	hpa := `{"type": "object", "properties": {"minReplicas": {"type": "integer"}}}`
+	network := func(from, to string) string {
+		return `{"kind": "edge", "type": "non-binding", "subType": "network", "selectors": [{"allow": {"from": [{"kind": "` + from + `"}], "to": [{"kind": "` + to + `"}]}}]}`
+	}
+
+	oldPkg := testPkgUnit(t, "v1.0.0", nil, network("Service", "Deployment"), network("Service", "Pod"))
+	oldPkg.Components = []component.ComponentDefinition{
+		testComponent(t, "HorizontalPodAutoscaler", "autoscaling/v1", hpa),
+		testComponent(t, "HorizontalPodAutoscaler", "autoscaling/v2", hpa),
+	}
+	newPkg := testPkgUnit(t, "v1.1.0", nil, network("Service", "Deployment"), network("Service", "StatefulSet"), network("Ingress", "Service"))
+	newPkg.Components = []component.ComponentDefinition{
+		testComponent(t, "HorizontalPodAutoscaler", "autoscaling/v1", hpa),
+		testComponent(t, "HorizontalPodAutoscaler", "autoscaling/v2", `{"type": "object", "properties": {"minReplicas": {"type": "integer"}, "behavior": {"type": "object"}}}`),
+	}
+
+	report := Diff(oldPkg, newPkg)
+
+	assert.Empty(t, report.AddedComponents)
+	assert.Empty(t, report.RemovedComponents)
+	require.Len(t, report.ChangedComponents, 1)
+	assert.Equal(t, "HorizontalPodAutoscaler@autoscaling/v2", report.ChangedComponents[0].Kind)
+	assert.Equal(t, []PropertyChange{{Path: "behavior", Change: PropertyAdded, NewType: "object"}}, report.ChangedComponents[0].Properties)
+
+	assert.Equal(t, []string{"edge/non-binding/network"}, report.ChangedRelationships)
+	assert.Equal(t, []string{"edge/non-binding/network"}, report.AddedRelationships)
+	assert.Empty(t, report.RemovedRelationships)
+	assert.False(t, report.Breaking)
+}
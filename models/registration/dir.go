@@ -54,15 +54,23 @@ func (d Dir) PkgUnit(regErrStore RegistrationErrorStore) (_ PackagingUnit, err e
 		return pkg, err
 	}
 
-	if reflect.ValueOf(pkg.Model).IsZero() {
-		errMsg := fmt.Errorf("model definition not found in imported package. Model definitions often use the filename `model.json`, but are not required to have this filename. One and exactly one entity containing schema: model.core must be present, otherwise the model package is considered malformed")
-		regErrStore.InsertEntityRegError("", "", entity.Model, filename, errMsg)
-		return pkg, errMsg
+	if err = ensureModelPresent(pkg, filename, regErrStore); err != nil {
+		return pkg, err
 	}
 
 	return pkg, nil
 }
 
+// ensureModelPresent reports a malformed package if no model definition was found while parsing it.
+func ensureModelPresent(pkg PackagingUnit, source string, regErrStore RegistrationErrorStore) error {
+	if reflect.ValueOf(pkg.Model).IsZero() {
+		errMsg := fmt.Errorf("model definition not found in imported package. Model definitions often use the filename `model.json`, but are not required to have this filename. One and exactly one entity containing schema: model.core must be present, otherwise the model package is considered malformed")
+		regErrStore.InsertEntityRegError("", "", entity.Model, source, errMsg)
+		return errMsg
+	}
+	return nil
+}
+
 func processDir(dirPath string, pkg *PackagingUnit, regErrStore RegistrationErrorStore) error {
 	var tempDirs []string
 	defer func() {
@@ -83,16 +91,7 @@ func processDir(dirPath string, pkg *PackagingUnit, regErrStore RegistrationErro
 		}
 
 		// Skip files that are not Meshery model entity definitions.
-		// The registration pipeline only understands JSON, YAML, and archive
-		ext := strings.ToLower(filepath.Ext(path))
-		nonEntityExtensions := map[string]bool{
-			".rego":     true, // OPA policy scripts (e.g. meshery-core/policies/)
-			".template": true, // Rego template files
-			".svg":      true, // SVG icon assets
-			".png":      true, // PNG image assets
-			".md":       true, // Markdown documentation
-		}
-		if nonEntityExtensions[ext] {
+		if isNonEntityFile(path) {
 			return nil
 		}
 
@@ -161,79 +160,102 @@ func processDir(dirPath string, pkg *PackagingUnit, regErrStore RegistrationErro
 			}
 		}
 
-		// Determine the entity type
-		entityType, err := utils.FindEntityType(content)
+		return processEntity(path, content, pkg, regErrStore)
+	})
+}
+
+// nonEntityExtensions lists the file extensions that are never Meshery model entity definitions.
+// The registration pipeline only understands JSON, YAML, and archive files.
+var nonEntityExtensions = map[string]bool{
+	".rego":     true, // OPA policy scripts (e.g. meshery-core/policies/)
+	".template": true, // Rego template files
+	".svg":      true, // SVG icon assets
+	".png":      true, // PNG image assets
+	".md":       true, // Markdown documentation
+}
+
+func isNonEntityFile(path string) bool {
+	return nonEntityExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+/*
+processEntity determines the entity type of the given JSON content and adds it to the PackagingUnit.
+`path` identifies the source of the content and is only used for error reporting.
+Invalid definitions are stored in the regErrStore and do not stop the processing of other entities.
+*/
+func processEntity(path string, content []byte, pkg *PackagingUnit, regErrStore RegistrationErrorStore) error {
+	// Determine the entity type
+	entityType, err := utils.FindEntityType(content)
+	if err != nil {
+		errMsg := meshkitFileUtils.ErrInvalidModel("import", filepath.Base(path), err)
+		regErrStore.InsertEntityRegError("", filepath.Base(path), entity.EntityType("unknown"), filepath.Base(path), errMsg)
+		regErrStore.AddInvalidDefinition(path, errMsg)
+		return nil
+	}
+
+	if entityType == "" {
+		// Not an entity we care about
+		return nil
+	}
+
+	// Get the entity
+	var e entity.Entity
+	e, err = getEntity(content)
+	if err != nil {
+		regErrStore.InsertEntityRegError("", "", entityType, filepath.Base(path), fmt.Errorf("could not get entity: %w", err))
+		regErrStore.AddInvalidDefinition(path, fmt.Errorf("could not get entity: %w", err))
+		return nil
+	}
+
+	// Add the entity to the packaging unit
+	switch e.Type() {
+	case entity.Model:
+		model, err := utils.Cast[*model.ModelDefinition](e)
 		if err != nil {
-			errMsg := meshkitFileUtils.ErrInvalidModel("import", filepath.Base(path), err)
-			regErrStore.InsertEntityRegError("", filepath.Base(path), entity.EntityType("unknown"), filepath.Base(path), errMsg)
-			regErrStore.AddInvalidDefinition(path, errMsg)
+			modelName := ""
+			if model != nil {
+				modelName = model.Name
+			}
+			regErrStore.InsertEntityRegError("", modelName, entityType, modelName, ErrGetEntity(err))
+			regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
 			return nil
 		}
-
-		if entityType == "" {
-			// Not an entity we care about
+		pkg.Model = *model
+	case entity.ComponentDefinition:
+		comp, err := utils.Cast[*component.ComponentDefinition](e)
+		if err != nil {
+			componentName := ""
+			if comp != nil {
+				componentName = comp.Component.Kind
+			}
+			regErrStore.InsertEntityRegError("", "", entityType, componentName, ErrGetEntity(err))
+			regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
 			return nil
 		}
-
-		// Get the entity
-		var e entity.Entity
-		e, err = getEntity(content)
+		pkg.Components = append(pkg.Components, *comp)
+	case entity.RelationshipDefinition:
+		rel, err := utils.Cast[*relationship.RelationshipDefinition](e)
 		if err != nil {
-			regErrStore.InsertEntityRegError("", "", entityType, filepath.Base(path), fmt.Errorf("could not get entity: %w", err))
-			regErrStore.AddInvalidDefinition(path, fmt.Errorf("could not get entity: %w", err))
+			relationshipName := ""
+			if rel != nil {
+				relationshipName = rel.Model.Name
+			}
+			regErrStore.InsertEntityRegError("", "", entityType, relationshipName, ErrGetEntity(err))
+			regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
 			return nil
 		}
-
-		// Add the entity to the packaging unit
-		switch e.Type() {
-		case entity.Model:
-			model, err := utils.Cast[*model.ModelDefinition](e)
-			if err != nil {
-				modelName := ""
-				if model != nil {
-					modelName = model.Name
-				}
-				regErrStore.InsertEntityRegError("", modelName, entityType, modelName, ErrGetEntity(err))
-				regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
-				return nil
-			}
-			pkg.Model = *model
-		case entity.ComponentDefinition:
-			comp, err := utils.Cast[*component.ComponentDefinition](e)
-			if err != nil {
-				componentName := ""
-				if comp != nil {
-					componentName = comp.Component.Kind
-				}
-				regErrStore.InsertEntityRegError("", "", entityType, componentName, ErrGetEntity(err))
-				regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
-				return nil
-			}
-			pkg.Components = append(pkg.Components, *comp)
-		case entity.RelationshipDefinition:
-			rel, err := utils.Cast[*relationship.RelationshipDefinition](e)
-			if err != nil {
-				relationshipName := ""
-				if rel != nil {
-					relationshipName = rel.Model.Name
-				}
-				regErrStore.InsertEntityRegError("", "", entityType, relationshipName, ErrGetEntity(err))
-				regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
-				return nil
-			}
-			pkg.Relationships = append(pkg.Relationships, *rel)
-		case entity.ConnectionDefinition:
-			conn, err := utils.Cast[*connectionv1beta3.ConnectionDefinition](e)
-			if err != nil {
-				regErrStore.InsertEntityRegError("", "", entityType, "", ErrGetEntity(err))
-				regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
-				return nil
-			}
-			pkg.Connections = append(pkg.Connections, *conn)
-		default:
-			// Unhandled entity type
+		pkg.Relationships = append(pkg.Relationships, *rel)
+	case entity.ConnectionDefinition:
+		conn, err := utils.Cast[*connectionv1beta3.ConnectionDefinition](e)
+		if err != nil {
+			regErrStore.InsertEntityRegError("", "", entityType, "", ErrGetEntity(err))
+			regErrStore.AddInvalidDefinition(path, ErrGetEntity(err))
 			return nil
 		}
+		pkg.Connections = append(pkg.Connections, *conn)
+	default:
+		// Unhandled entity type
 		return nil
-	})
+	}
+	return nil
 }
@@ -7,12 +7,18 @@ import (
 )
 
 const (
-	ErrDirPkgUnitParseFailCode = "meshkit-11267"
-	ErrGetEntityCode           = "meshkit-11268"
-	ErrRegisterEntityCode      = "meshkit-11269"
-	ErrImportFailureCode       = "meshkit-11270"
-	ErrMissingRegistrantCode   = "meshkit-11271"
-	ErrSeedingComponentsCode   = "meshkit-11272"
+	ErrDirPkgUnitParseFailCode      = "meshkit-11267"
+	ErrGetEntityCode                = "meshkit-11268"
+	ErrRegisterEntityCode           = "meshkit-11269"
+	ErrImportFailureCode            = "meshkit-11270"
+	ErrMissingRegistrantCode        = "meshkit-11271"
+	ErrSeedingComponentsCode        = "meshkit-11272"
+	ErrTarPkgUnitParseFailCode      = "meshkit-11328"
+	ErrOCIImagePkgUnitParseFailCode = "meshkit-11329"
+	ErrAtomicRegistrationCode       = "meshkit-11330"
+	ErrGetRegisteredPkgUnitCode     = "meshkit-11333"
+	ErrExportModelCode              = "meshkit-11335"
+	ErrWriteSVGCode                 = "meshkit-11350"
 )
 
 func ErrSeedingComponents(err error) error {
@@ -71,6 +77,72 @@ func ErrDirPkgUnitParseFail(dirpath string, err error) error {
 	)
 }
 
+func ErrTarPkgUnitParseFail(archive string, err error) error {
+	return errors.New(
+		ErrTarPkgUnitParseFailCode,
+		errors.Alert,
+		[]string{fmt.Sprintf("Archive: %s cannot be registered into Meshery", archive)},
+		[]string{err.Error()},
+		[]string{"The archive might not be a valid tar or tar.gz file", "The archive might be truncated or corrupted"},
+		[]string{"Make sure that the archive is a valid tar or tar.gz file containing a valid model definition", "Try to recreate the archive and import it again"},
+	)
+}
+
+func ErrOCIImagePkgUnitParseFail(image string, err error) error {
+	return errors.New(
+		ErrOCIImagePkgUnitParseFailCode,
+		errors.Alert,
+		[]string{fmt.Sprintf("OCI image: %s cannot be registered into Meshery", image)},
+		[]string{err.Error()},
+		[]string{"The image layers might not be readable", "The image might not have been built from a valid model package"},
+		[]string{"Make sure that the image is a valid OCI image built from a model package, for example using `oci.BuildImage`", "Try to rebuild or pull the image again"},
+	)
+}
+
+func ErrAtomicRegistration(modelName string) error {
+	return errors.New(
+		ErrAtomicRegistrationCode,
+		errors.Alert,
+		[]string{fmt.Sprintf("Registration of model: %s has been rolled back", modelName)},
+		[]string{"One or more entities of the model failed to register and the model was registered atomically"},
+		[]string{"Model, component, relationship or connection definitions might be violating the definition schema", "Registry might be inaccessible at the moment"},
+		[]string{"See the registration errors of the individual entities of the model for more details", "Fix the failing definitions and register the model again"},
+	)
+}
+
+func ErrGetRegisteredPkgUnit(err error, name, version string) error {
+	return errors.New(
+		ErrGetRegisteredPkgUnitCode,
+		errors.Alert,
+		[]string{fmt.Sprintf("Failed to read model: %s, version: %s from Meshery's registry", name, version)},
+		[]string{err.Error()},
+		[]string{"The model version might not be registered", "Registry might be inaccessible at the moment"},
+		[]string{"Make sure that the model version is registered", "If the registry is inaccesible, please try again after some time"},
+	)
+}
+
+func ErrExportModel(err error, name, version string) error {
+	return errors.New(
+		ErrExportModelCode,
+		errors.Alert,
+		[]string{fmt.Sprintf("Failed to export model: %s, version: %s", name, version)},
+		[]string{err.Error()},
+		[]string{"The model version might not be registered", "The export format might not be supported", "The destination might not be writable"},
+		[]string{"Make sure that the model version is registered", "Use one of the dir, tar.gz or oci formats", "Check the permissions of the destination"},
+	)
+}
+
+func ErrWriteSVG(err error, modelName string) error {
+	return errors.New(
+		ErrWriteSVGCode,
+		errors.Alert,
+		[]string{fmt.Sprintf("Failed to write the SVGs of model: %s", modelName)},
+		[]string{err.Error()},
+		[]string{"The SVG directory might not be writable", "The disk might be full"},
+		[]string{"Check the permissions of the SVG directory and the free disk space", "Register the model again to rewrite its SVGs"},
+	)
+}
+
 func ErrImportFailure(hostname string, failedMsg string) error {
 	return errors.New(
 		ErrImportFailureCode,
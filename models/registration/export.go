@@ -0,0 +1,164 @@
+package registration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/meshery/meshkit/models/oci"
+	"github.com/meshery/meshkit/utils"
+)
+
+type ExportFormat string
+
+const (
+	// ExportFormatDir writes the model to a directory, which can be registered using `Dir`.
+	ExportFormatDir ExportFormat = "dir"
+	// ExportFormatTarGz writes the model to a gzipped tarball, which can be registered using `Dir` or `Tar`.
+	ExportFormatTarGz ExportFormat = "tar.gz"
+	// ExportFormatOCI writes the model to an OCI image tarball, which can be registered using `Dir` or `OCIImage`.
+	ExportFormatOCI ExportFormat = "oci"
+)
+
+/*
+ExportModel writes a registered model version to `dest` in the given format, so that it can be registered into another registry.
+The layout is the one consumed by `Dir`:
+
+	<name>/<version>/model.json
+	<name>/<version>/components/<kind>-<apiVersion>-<id>.json
+	<name>/<version>/relationships/<kind>-<type>-<subType>-<id>.json
+	<name>/<version>/connections/<name>-<id>.json
+
+SVGs written to the `svgBaseDir` on registration are read back and embedded in the definitions.
+For the `dir` format `dest` is a directory, otherwise it is the path of the file to create.
+*/
+func (rh *RegistrationHelper) ExportModel(name, version string, format ExportFormat, dest string) error {
+	pkg, err := GetRegisteredPkgUnit(rh.regManager, name, version)
+	if err != nil {
+		return ErrExportModel(err, name, version)
+	}
+	rh.embedSVGs(&pkg)
+
+	switch format {
+	case ExportFormatDir:
+		err = writePkgUnit(pkg, dest)
+	case ExportFormatTarGz, ExportFormatOCI:
+		err = exportArchive(pkg, format, dest)
+	default:
+		err = fmt.Errorf("unsupported export format: %s", format)
+	}
+	if err != nil {
+		return ErrExportModel(err, name, version)
+	}
+	return nil
+}
+
+func exportArchive(pkg PackagingUnit, format ExportFormat, dest string) error {
+	tempDir, err := os.MkdirTemp("", "model-export-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	if err := writePkgUnit(pkg, tempDir); err != nil {
+		return err
+	}
+
+	if format == ExportFormatOCI {
+		img, err := oci.BuildImage(tempDir)
+		if err != nil {
+			return err
+		}
+		return oci.SaveOCIArtifact(img, dest, pkg.Model.Name)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if err := utils.Compress(tempDir, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writePkgUnit writes the definitions of the PackagingUnit to `<dir>/<name>/<version>`.
+func writePkgUnit(pkg PackagingUnit, dir string) error {
+	modelDir := filepath.Join(dir, pkg.Model.Name, pkg.Model.Model.Version)
+	for _, sub := range []string{"components", "relationships", "connections"} {
+		if err := os.MkdirAll(filepath.Join(modelDir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := utils.WriteJSONToFile(filepath.Join(modelDir, "model.json"), pkg.Model); err != nil {
+		return err
+	}
+	for _, comp := range pkg.Components {
+		// The model is written once, alongside the components.
+		comp.Model = nil
+		// Components of the same kind may be defined for several API versions, e.g. autoscaling/v1 and autoscaling/v2.
+		filename := exportFilename(fmt.Sprintf("%s-%s-%s", comp.Component.Kind, comp.Component.Version, comp.ID))
+		if err := utils.WriteJSONToFile(filepath.Join(modelDir, "components", filename), comp); err != nil {
+			return err
+		}
+	}
+	for _, rel := range pkg.Relationships {
+		filename := exportFilename(fmt.Sprintf("%s-%s-%s-%s", rel.Kind, rel.RelationshipType, rel.SubType, rel.ID))
+		if err := utils.WriteJSONToFile(filepath.Join(modelDir, "relationships", filename), rel); err != nil {
+			return err
+		}
+	}
+	for _, conn := range pkg.Connections {
+		filename := exportFilename(fmt.Sprintf("%s-%s", conn.Name, conn.ID))
+		if err := utils.WriteJSONToFile(filepath.Join(modelDir, "connections", filename), conn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportFilename(name string) string {
+	return strings.NewReplacer("/", "-", "\\", "-", " ", "-").Replace(name) + ".json"
+}
+
+// embedSVGs replaces the paths of the SVGs written by `WriteAndReplaceSVGWithFileSystemPath` with their contents.
+func (rh *RegistrationHelper) embedSVGs(pkg *PackagingUnit) {
+	if pkg.Model.Metadata != nil {
+		pkg.Model.Metadata.SvgColor = rh.readSVG(pkg.Model.Metadata.SvgColor)
+		pkg.Model.Metadata.SvgWhite = rh.readSVG(pkg.Model.Metadata.SvgWhite)
+		if pkg.Model.Metadata.SvgComplete != nil {
+			svgComplete := rh.readSVG(*pkg.Model.Metadata.SvgComplete)
+			pkg.Model.Metadata.SvgComplete = &svgComplete
+		}
+	}
+	for i := range pkg.Components {
+		styles := pkg.Components[i].Styles
+		if styles == nil {
+			continue
+		}
+		styles.SvgColor = rh.readSVG(styles.SvgColor)
+		styles.SvgWhite = rh.readSVG(styles.SvgWhite)
+		styles.SvgComplete = rh.readSVG(styles.SvgComplete)
+	}
+}
+
+// readSVG returns the contents of the SVG file referenced by svgPath, or svgPath itself if it does not reference a file inside the svgBaseDir.
+func (rh *RegistrationHelper) readSVG(svgPath string) string {
+	if svgPath == "" || rh.svgBaseDir == "" || strings.HasPrefix(strings.TrimSpace(svgPath), "<") {
+		return svgPath
+	}
+	// Paths are stored relative to the UI, see getRelativePathForAPI.
+	rel := strings.TrimPrefix(svgPath, strings.TrimPrefix(rh.svgBaseDir, "../../"))
+	fullPath := filepath.Join(rh.svgBaseDir, rel)
+	if r, err := filepath.Rel(rh.svgBaseDir, fullPath); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return svgPath
+	}
+	svg, err := os.ReadFile(fullPath)
+	if err != nil {
+		return svgPath
+	}
+	return string(svg)
+}
@@ -0,0 +1,81 @@
+package registration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePkgUnitRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	pkg := testPkgUnit(t, "v1.0.0", map[string]string{
+		"Deployment": `{"type": "object"}`,
+	})
+	require.NoError(t, json.Unmarshal([]byte(testModelDocument), &pkg.Model))
+	for i := range pkg.Components {
+		pkg.Components[i].SchemaVersion = "components.meshery.io/v1beta1"
+	}
+	var rel relationship.RelationshipDefinition
+	require.NoError(t, json.Unmarshal([]byte(testRelationshipDocument), &rel))
+	pkg.Relationships = append(pkg.Relationships, rel)
+
+	dir := t.TempDir()
+	require.NoError(t, writePkgUnit(pkg, dir))
+	assert.FileExists(t, filepath.Join(dir, "test-model", "v1.0.0", "model.json"))
+	assert.FileExists(t, filepath.Join(dir, "test-model", "v1.0.0", "components", "Deployment-v1-"+pkg.Components[0].ID.String()+".json"))
+
+	store := newTestRegErrStore()
+	parsed, err := NewDir(dir).PkgUnit(store)
+	require.NoError(t, err)
+	assert.Empty(t, store.entityErrors)
+	assert.Equal(t, "test-model", parsed.Model.Name)
+	require.Len(t, parsed.Components, 1)
+	assert.Equal(t, "Deployment", parsed.Components[0].Component.Kind)
+	require.Len(t, parsed.Relationships, 1)
+	assert.Equal(t, "firewall", parsed.Relationships[0].SubType)
+}
+
+func TestWritePkgUnitSameKind(t *testing.T) {
+	t.Parallel()
+
+	pkg := testPkgUnit(t, "v1.0.0", nil)
+	for _, apiVersion := range []string{"autoscaling/v1", "autoscaling/v2"} {
+		var comp component.ComponentDefinition
+		require.NoError(t, json.Unmarshal([]byte(`{"component": {"kind": "HorizontalPodAutoscaler", "version": "`+apiVersion+`"}}`), &comp))
+		comp.ID = uuid.Must(uuid.NewV4())
+		pkg.Components = append(pkg.Components, comp)
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, writePkgUnit(pkg, dir))
+
+	parsed, err := NewDir(dir).PkgUnit(newTestRegErrStore())
+	require.NoError(t, err)
+	apiVersions := []string{}
+	for _, comp := range parsed.Components {
+		apiVersions = append(apiVersions, comp.Component.Version)
+	}
+	assert.ElementsMatch(t, []string{"autoscaling/v1", "autoscaling/v2"}, apiVersions)
+}
+
+func TestReadSVG(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "test-model", "color"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "test-model", "color", "pod-color.svg"), []byte("<svg/>"), 0644))
+	rh := RegistrationHelper{svgBaseDir: base}
+
+	assert.Equal(t, "<svg/>", rh.readSVG(filepath.Join(base, "test-model", "color", "pod-color.svg")))
+	assert.Equal(t, "<svg>inline</svg>", rh.readSVG("<svg>inline</svg>"))
+	assert.Equal(t, "../escape.svg", rh.readSVG("../escape.svg"))
+	assert.Equal(t, "test-model/color/missing.svg", rh.readSVG("test-model/color/missing.svg"))
+}
@@ -1,14 +1,125 @@
 package registration
 
 import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
 	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/meshery/meshkit/models/meshmodel/entity"
 )
 
 type OCIImage struct {
-	_ gcrv1.Image
+	name string
+	img  gcrv1.Image
+}
+
+/*
+NewOCIImage accepts an OCI image, such as the one produced by `oci.BuildImage`.
+The image can be loaded from an OCI tarball (`tarball.ImageFromPath`), an OCI layout or a (local) registry.
+`name` identifies the image (usually the image reference) and is only used while reporting errors.
+*/
+func NewOCIImage(name string, img gcrv1.Image) OCIImage {
+	return OCIImage{name: name, img: img}
 }
 
-func (o OCIImage) PkgUnit(regErrStore RegistrationErrorStore) (PackagingUnit, error) {
+/*
+PkgUnit walks all the layers of the image and finds out if they contain any valid meshery definitions. Valid meshery definitions are added to the PackagingUnit struct.
+Layers can either be (gzip compressed) tarballs containing the model directory or static layers containing a single definition.
+Invalid definitions are stored in the regErrStore with error data.
+*/
+func (o OCIImage) PkgUnit(regErrStore RegistrationErrorStore) (_ PackagingUnit, err error) {
 	pkg := PackagingUnit{}
+
+	if o.img == nil {
+		err = ErrOCIImagePkgUnitParseFail(o.name, fmt.Errorf("no image provided"))
+		regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), o.name, err)
+		return pkg, err
+	}
+
+	err = processImage(o.name, o.img, &pkg, regErrStore)
+	if err != nil {
+		err = ErrOCIImagePkgUnitParseFail(o.name, err)
+		regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), o.name, err)
+		return pkg, err
+	}
+
+	if err = ensureModelPresent(pkg, o.name, regErrStore); err != nil {
+		return pkg, err
+	}
+
 	return pkg, nil
 }
+
+// processImage decodes every layer of the image into the PackagingUnit.
+// Only an unreadable image is reported as an error, invalid layers are recorded in the regErrStore.
+func processImage(imageName string, img gcrv1.Image, pkg *PackagingUnit, regErrStore RegistrationErrorStore) error {
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range layers {
+		layerName := imageName
+		if digest, err := layer.Digest(); err == nil {
+			layerName = fmt.Sprintf("%s@%s", imageName, digest.String())
+		}
+
+		data, err := readLayer(layer)
+		if err != nil {
+			err = ErrOCIImagePkgUnitParseFail(layerName, err)
+			regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), layerName, err)
+			regErrStore.AddInvalidDefinition(layerName, err)
+			continue
+		}
+
+		// Layers built with `oci.LayerTypeTarball` hold the gzip compressed model directory.
+		if isGzip(data) || isTar(data) {
+			if err := processTar(layerName, bytes.NewReader(data), pkg, regErrStore); err != nil {
+				err = ErrOCIImagePkgUnitParseFail(layerName, err)
+				regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), layerName, err)
+				regErrStore.AddInvalidDefinition(layerName, err)
+			}
+			continue
+		}
+
+		// Layers built with `oci.LayerTypeStatic` hold the content of a single definition.
+		// They are named by digest, so YAML content is told apart by the layer's media type.
+		mediaType, _ := layer.MediaType()
+		if err := processEntityData(layerName, data, strings.Contains(string(mediaType), "yaml"), pkg, regErrStore); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readLayer(layer gcrv1.Layer) ([]byte, error) {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	return io.ReadAll(rc)
+}
+
+// imageFromArchive loads an OCI image from the content of an (optionally gzip compressed) image tarball (see `oci.SaveOCIArtifact`).
+func imageFromArchive(data []byte) (gcrv1.Image, error) {
+	if isGzip(data) {
+		gzr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gzr.Close() }()
+		data, err = io.ReadAll(gzr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	opener := func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return tarball.Image(opener, nil)
+}
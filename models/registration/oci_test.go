@@ -0,0 +1,55 @@
+package registration
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOCIImagePkgUnitTarballLayer(t *testing.T) {
+	t.Parallel()
+
+	layer := static.NewLayer(buildTestTar(t, map[string]string{
+		"test-model/model.json":                 testModelDocument,
+		"test-model/relationships/binding.json": testRelationshipDocument,
+	}, true), types.OCILayer)
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	require.NoError(t, err)
+
+	pkg, err := NewOCIImage("test-model:latest", img).PkgUnit(newTestRegErrStore())
+	require.NoError(t, err)
+	assert.Equal(t, "test-model", pkg.Model.Name)
+	assert.Len(t, pkg.Relationships, 1)
+}
+
+func TestOCIImagePkgUnitStaticLayers(t *testing.T) {
+	t.Parallel()
+
+	img, err := mutate.AppendLayers(empty.Image,
+		static.NewLayer([]byte(testModelDocument), types.MediaType("application/vnd.cncf.flux.content.v1.json")),
+		static.NewLayer([]byte(testRelationshipDocument), types.MediaType("application/vnd.cncf.flux.content.v1.json")),
+		static.NewLayer([]byte(`{"kind": "Pod"}`), types.MediaType("application/vnd.cncf.flux.content.v1.json")),
+	)
+	require.NoError(t, err)
+
+	regErrStore := newTestRegErrStore()
+	pkg, err := NewOCIImage("test-model:latest", img).PkgUnit(regErrStore)
+	require.NoError(t, err)
+	assert.Equal(t, "test-model", pkg.Model.Name)
+	assert.Len(t, pkg.Relationships, 1)
+	assert.Len(t, regErrStore.invalidDefinitions, 1)
+}
+
+func TestOCIImagePkgUnitWithoutImage(t *testing.T) {
+	t.Parallel()
+
+	regErrStore := newTestRegErrStore()
+	_, err := NewOCIImage("missing", nil).PkgUnit(regErrStore)
+	require.Error(t, err)
+	assert.NotEmpty(t, regErrStore.entityErrors)
+}
@@ -25,6 +25,9 @@ type RegistrationHelper struct {
 	regErrStore RegistrationErrorStore
 	svgBaseDir  string
 	PkgUnits    []PackagingUnit // Store successfully registered packagingUnits
+	// When Atomic is true, each PackagingUnit is registered inside a single database transaction.
+	// A failure to register any of its entities rolls back the whole model.
+	Atomic bool
 }
 
 func NewRegistrationHelper(svgBaseDir string, regm *meshmodel.RegistryManager, regErrStore RegistrationErrorStore) RegistrationHelper {
@@ -47,24 +50,78 @@ func (rh *RegistrationHelper) Register(entity RegisterableEntity) {
 /*
 register will return an error if it is not able to register the `model`.
 If there are errors when registering other entities, they are handled properly but does not stop the registration process.
+In Atomic mode, such errors roll back the registration of the whole PackagingUnit once all of its entities have been attempted.
 */
 func (rh *RegistrationHelper) register(pkg PackagingUnit) {
+	if !rh.Atomic {
+		registered, ok, _ := rh.registerPackagingUnit(rh.regManager, pkg, &svgWriter{baseDir: rh.svgBaseDir}, false)
+		if ok {
+			// Store the successfully registered PackagingUnit
+			rh.PkgUnits = append(rh.PkgUnits, registered)
+		}
+		return
+	}
+
+	var registered PackagingUnit
+	var ok bool
+	// The SVGs are only written once the registration is committed, so that a rollback leaves nothing behind.
+	svgs := &svgWriter{baseDir: rh.svgBaseDir, deferred: true}
+	err := rh.regManager.Transaction(func(txm *meshmodel.RegistryManager) error {
+		var failed bool
+		registered, ok, failed = rh.registerPackagingUnit(txm, pkg, svgs, true)
+		if failed {
+			return ErrAtomicRegistration(pkg.Model.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		rh.regErrStore.InsertEntityRegError(pkg.Model.Registrant.Kind, "", entity.Model, pkg.Model.Name, err)
+		return
+	}
+	if err := svgs.flush(); err != nil {
+		rh.regErrStore.InsertEntityRegError(pkg.Model.Registrant.Kind, "", entity.Model, pkg.Model.Name, ErrWriteSVG(err, pkg.Model.Name))
+	}
+	if ok {
+		// Store the successfully registered PackagingUnit
+		rh.PkgUnits = append(rh.PkgUnits, registered)
+	}
+}
+
+/*
+registerPackagingUnit registers the entities of the PackagingUnit through the given RegistryManager, writing their SVGs
+through `svgs`. It returns the PackagingUnit holding only the successfully registered entities, whether the model got
+registered, and whether registering any of the entities failed. All failures are stored in the regErrStore.
+When `savepoints` is true every entity is registered inside its own nested transaction, so that a failure
+does not abort the transaction the RegistryManager is bound to and the remaining entities can still be attempted.
+*/
+func (rh *RegistrationHelper) registerPackagingUnit(rm *meshmodel.RegistryManager, pkg PackagingUnit, svgs *svgWriter, savepoints bool) (_ PackagingUnit, registered bool, failed bool) {
+	registerEntity := func(h connectionv1beta3.Connection, en entity.Entity) error {
+		if !savepoints {
+			_, _, err := rm.RegisterEntity(h, en)
+			return err
+		}
+		return rm.Transaction(func(txm *meshmodel.RegistryManager) error {
+			_, _, err := txm.RegisterEntity(h, en)
+			return err
+		})
+	}
+
 	if len(pkg.Components) == 0 && len(pkg.Relationships) == 0 {
 		//silently exit if the model does not conatin any components or relationships
-		return
+		return pkg, false, false
 	}
 	ignored := model.ModelDefinitionStatusIgnored
 	// 1. Register the model
 	model := pkg.Model
 	modelstatus := model.Status
 	if modelstatus == ignored {
-		return
+		return pkg, false, false
 	}
 	// Don't register anything else if registrant is not there
 	if model.Registrant.Kind == "" {
 		err := ErrMissingRegistrant(model.Name)
 		rh.regErrStore.InsertEntityRegError(model.Registrant.Kind, "", entity.Model, model.Name, err)
-		return
+		return pkg, false, true
 	}
 
 	if model.Metadata != nil {
@@ -76,11 +133,10 @@ func (rh *RegistrationHelper) register(pkg PackagingUnit) {
 		var svgCompletePath string
 
 		// Write SVG for models
-		model.Metadata.SvgColor, model.Metadata.SvgWhite, svgCompletePath = WriteAndReplaceSVGWithFileSystemPath(
+		model.Metadata.SvgColor, model.Metadata.SvgWhite, svgCompletePath = svgs.write(
 			model.Metadata.SvgColor,
 			model.Metadata.SvgWhite,
 			svgComplete,
-			rh.svgBaseDir,
 			model.Name,
 			model.Name,
 			true,
@@ -95,13 +151,13 @@ func (rh *RegistrationHelper) register(pkg PackagingUnit) {
 	// registry now expects a v1beta3 host. Adapt it once and reuse for every entity
 	// registered under this model so they all attach to the same registrant.
 	host := meshmodel.RegistrantHostToV1beta3(model.Registrant)
-	_, _, err := rh.regManager.RegisterEntity(host, &model)
+	err := registerEntity(host, &model)
 
 	// If model cannot be registered, don't register anything else
 	if err != nil {
 		err = ErrRegisterEntity(err, string(model.Type()), model.DisplayName)
 		rh.regErrStore.InsertEntityRegError(model.Registrant.Kind, "", entity.Model, model.Name, err)
-		return
+		return pkg, false, true
 	}
 
 	hostname := model.Registrant.Kind
@@ -120,21 +176,21 @@ func (rh *RegistrationHelper) register(pkg PackagingUnit) {
 
 		if comp.Styles != nil {
 			// Write SVG for components
-			comp.Styles.SvgColor, comp.Styles.SvgWhite, comp.Styles.SvgComplete = WriteAndReplaceSVGWithFileSystemPath(
+			comp.Styles.SvgColor, comp.Styles.SvgWhite, comp.Styles.SvgComplete = svgs.write(
 				comp.Styles.SvgColor,
 				comp.Styles.SvgWhite,
 				comp.Styles.SvgComplete,
-				rh.svgBaseDir,
 				comp.Model.Name,
 				comp.Component.Kind,
 				false,
 			)
 		}
 
-		_, _, err := rh.regManager.RegisterEntity(host, &comp)
+		err := registerEntity(host, &comp)
 		if err != nil {
 			err = ErrRegisterEntity(err, string(comp.Type()), comp.DisplayName)
 			rh.regErrStore.InsertEntityRegError(hostname, model.DisplayName, entity.ComponentDefinition, comp.DisplayName, err)
+			failed = true
 		} else {
 			// Successful registration, add to successfulComponents
 			registeredComponents = append(registeredComponents, comp)
@@ -145,10 +201,11 @@ func (rh *RegistrationHelper) register(pkg PackagingUnit) {
 	for _, rel := range pkg.Relationships {
 		rel.Model = model.ToReference()
 		rel.ModelId = &model.ID
-		_, _, err := rh.regManager.RegisterEntity(host, &rel)
+		err := registerEntity(host, &rel)
 		if err != nil {
 			err = ErrRegisterEntity(err, string(rel.Type()), string(rel.Kind))
 			rh.regErrStore.InsertEntityRegError(hostname, model.DisplayName, entity.RelationshipDefinition, rel.ID.String(), err)
+			failed = true
 		} else {
 			// Successful registration, add to successfulRelationships
 			registeredRelationships = append(registeredRelationships, rel)
@@ -160,10 +217,11 @@ func (rh *RegistrationHelper) register(pkg PackagingUnit) {
 	for _, conn := range pkg.Connections {
 		ref := model.ToReference()
 		conn.ModelReference = &ref
-		_, _, err := rh.regManager.RegisterEntity(host, &conn)
+		err := registerEntity(host, &conn)
 		if err != nil {
 			err = ErrRegisterEntity(err, string(conn.Type()), conn.Name)
 			rh.regErrStore.InsertEntityRegError(hostname, model.DisplayName, entity.ConnectionDefinition, conn.Name, err)
+			failed = true
 		} else {
 			// Successful registration, add to successfulConnections
 			registeredConnections = append(registeredConnections, conn)
@@ -175,6 +233,5 @@ func (rh *RegistrationHelper) register(pkg PackagingUnit) {
 	pkg.Relationships = registeredRelationships
 	pkg.Connections = registeredConnections
 	pkg.Model = model
-	// Store the successfully registered PackagingUnit
-	rh.PkgUnits = append(rh.PkgUnits, pkg)
+	return pkg, true, failed
 }
@@ -0,0 +1,84 @@
+package registration_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	meshkiterrors "github.com/meshery/meshkit/errors"
+	meshmodel "github.com/meshery/meshkit/models/meshmodel/registry"
+	"github.com/meshery/meshkit/models/registration"
+	"github.com/meshery/meshkit/models/registration/registrationtest"
+	"github.com/meshery/schemas/models/v1beta1/model"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// failComponentCreate makes the registration of the components of the given kind fail.
+func failComponentCreate(t *testing.T, db *gorm.DB, kind string) {
+	t.Helper()
+
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail_component", func(tx *gorm.DB) {
+		if !tx.Statement.ReflectValue.IsValid() {
+			return
+		}
+		var comp *component.ComponentDefinition
+		switch dest := tx.Statement.ReflectValue.Interface().(type) {
+		case component.ComponentDefinition:
+			comp = &dest
+		case *component.ComponentDefinition:
+			comp = dest
+		}
+		if comp != nil && comp.Component.Kind == kind {
+			_ = tx.AddError(errors.New("component rejected by the test"))
+		}
+	}))
+}
+
+func TestRegisterAtomic(t *testing.T) {
+	h := registrationtest.NewHarness(t)
+	h.Helper.Atomic = true
+	h.Register(registration.NewDir(roundTripFixture))
+
+	require.Len(t, h.Helper.PkgUnits, 1)
+	assert.Len(t, h.Snapshot(roundTripModel).Components, 4)
+	_, err := os.Stat(filepath.Join(h.SVGBaseDir, roundTripModel, "color", roundTripModel+"-color.svg"))
+	assert.NoError(t, err, "the SVGs are written once the registration is committed")
+}
+
+func TestRegisterAtomic_Rollback(t *testing.T) {
+	h := registrationtest.NewHarness(t)
+	h.Helper.Atomic = true
+	failComponentCreate(t, h.DB.DB, "Service")
+	h.Helper.Register(registration.NewDir(roundTripFixture))
+
+	assert.NotEmpty(t, h.Errors.EntityErrors)
+	assert.Empty(t, h.Helper.PkgUnits)
+	assert.Equal(t, registrationtest.Snapshot{}, h.Snapshot(roundTripModel))
+	for _, table := range []interface{}{&model.ModelDefinition{}, &component.ComponentDefinition{}, &meshmodel.Registry{}} {
+		var count int64
+		require.NoError(t, h.DB.Model(table).Count(&count).Error)
+		assert.Zero(t, count, "%T rows left after the rollback", table)
+	}
+	search, err := h.Registry.Search(meshmodel.SearchQuery{Query: "Deployment"})
+	require.NoError(t, err)
+	assert.Empty(t, search.Hits, "the search index only holds committed entities")
+	entries, err := os.ReadDir(h.SVGBaseDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no SVG is written for a rolled back registration")
+}
+
+func TestRegisterAtomic_SVGWriteFailure(t *testing.T) {
+	h := registrationtest.NewHarness(t)
+	h.Helper.Atomic = true
+	// A file in place of the SVG directory of the model makes writing its SVGs fail.
+	require.NoError(t, os.WriteFile(filepath.Join(h.SVGBaseDir, roundTripModel), nil, 0600))
+	h.Helper.Register(registration.NewDir(roundTripFixture))
+
+	require.Len(t, h.Helper.PkgUnits, 1, "the committed registration is kept")
+	require.Len(t, h.Errors.EntityErrors, 1)
+	assert.Equal(t, registration.ErrWriteSVGCode, meshkiterrors.GetCode(h.Errors.EntityErrors[0]))
+}
@@ -0,0 +1,74 @@
+package registration
+
+import (
+	"fmt"
+
+	meshmodel "github.com/meshery/meshkit/models/meshmodel/registry"
+	"github.com/meshery/meshkit/models/meshmodel/registry/v1alpha3"
+	regv1beta1 "github.com/meshery/meshkit/models/meshmodel/registry/v1beta1"
+	"github.com/meshery/meshkit/utils"
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	"github.com/meshery/schemas/models/v1beta1/model"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	connectionv1beta3 "github.com/meshery/schemas/models/v1beta3/connection"
+)
+
+/*
+GetRegisteredPkgUnit builds the PackagingUnit of a model version already registered in the registry,
+including the component schemas, relationships and connection definitions stored by the RegistryManager.
+If the same model version is registered by multiple registrants, the first one is used.
+*/
+func GetRegisteredPkgUnit(rm *meshmodel.RegistryManager, name, version string) (PackagingUnit, error) {
+	pkg := PackagingUnit{}
+
+	models, _, _, err := rm.GetEntities(&regv1beta1.ModelFilter{Name: name, Version: version, Limit: 1})
+	if err != nil {
+		return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+	}
+	if len(models) == 0 {
+		return pkg, ErrGetRegisteredPkgUnit(fmt.Errorf("model is not registered"), name, version)
+	}
+	m, err := utils.Cast[*model.ModelDefinition](models[0])
+	if err != nil {
+		return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+	}
+	pkg.Model = *m
+
+	components, _, _, err := rm.GetEntities(&regv1beta1.ComponentFilter{ModelName: name, Version: version})
+	if err != nil {
+		return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+	}
+	for _, e := range components {
+		comp, err := utils.Cast[*component.ComponentDefinition](e)
+		if err != nil {
+			return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+		}
+		pkg.Components = append(pkg.Components, *comp)
+	}
+
+	relationships, _, _, err := rm.GetEntities(&v1alpha3.RelationshipFilter{ModelName: name, Version: version})
+	if err != nil {
+		return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+	}
+	for _, e := range relationships {
+		rel, err := utils.Cast[*relationship.RelationshipDefinition](e)
+		if err != nil {
+			return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+		}
+		pkg.Relationships = append(pkg.Relationships, *rel)
+	}
+
+	connections, _, _, err := rm.GetEntities(&regv1beta1.ConnectionFilter{ModelName: name, Version: version})
+	if err != nil {
+		return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+	}
+	for _, e := range connections {
+		conn, err := utils.Cast[*connectionv1beta3.ConnectionDefinition](e)
+		if err != nil {
+			return pkg, ErrGetRegisteredPkgUnit(err, name, version)
+		}
+		pkg.Connections = append(pkg.Connections, *conn)
+	}
+
+	return pkg, nil
+}
@@ -0,0 +1,236 @@
+// Package registrationtest provides a harness to test the registration of models: it registers them into an
+// in-memory registry, reads them back through the registry filters and compares the result with golden files.
+package registrationtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/meshery/meshkit/database"
+	corev1beta1 "github.com/meshery/meshkit/models/meshmodel/core/v1beta1"
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	meshmodel "github.com/meshery/meshkit/models/meshmodel/registry"
+	"github.com/meshery/meshkit/models/meshmodel/registry/v1alpha3"
+	regv1beta1 "github.com/meshery/meshkit/models/meshmodel/registry/v1beta1"
+	"github.com/meshery/meshkit/models/registration"
+	"github.com/meshery/schemas/models/v1alpha3/relationship"
+	"github.com/meshery/schemas/models/v1beta1/category"
+	"github.com/meshery/schemas/models/v1beta1/model"
+	"github.com/meshery/schemas/models/v1beta3/component"
+	connectionv1beta3 "github.com/meshery/schemas/models/v1beta3/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+// ErrorStore records the errors of the registration, see registration.RegistrationErrorStore.
+type ErrorStore struct {
+	InvalidDefinitions map[string]error
+	EntityErrors       []error
+}
+
+// NewErrorStore returns an empty ErrorStore.
+func NewErrorStore() *ErrorStore {
+	return &ErrorStore{InvalidDefinitions: map[string]error{}}
+}
+
+func (s *ErrorStore) AddInvalidDefinition(path string, err error) {
+	s.InvalidDefinitions[path] = err
+}
+
+func (s *ErrorStore) InsertEntityRegError(_ string, _ string, _ entity.EntityType, _ string, err error) {
+	s.EntityErrors = append(s.EntityErrors, err)
+}
+
+// Harness registers models into an in-memory registry and reads them back through the registry filters.
+type Harness struct {
+	t          testing.TB
+	DB         *database.Handler
+	Registry   *meshmodel.RegistryManager
+	Helper     registration.RegistrationHelper
+	Errors     *ErrorStore
+	SVGBaseDir string
+}
+
+// NewHarness returns a harness over a new in-memory registry, which is dropped at the end of the test.
+func NewHarness(t testing.TB) *Harness {
+	t.Helper()
+
+	db, err := database.New(database.Options{
+		Engine:   database.SQLITE,
+		Filename: ":memory:",
+	})
+	require.NoError(t, err)
+	rm, err := meshmodel.NewRegistryManager(&db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		rm.Cleanup()
+		assert.NoError(t, db.DBClose())
+	})
+
+	store := NewErrorStore()
+	svgBaseDir := t.TempDir()
+	return &Harness{
+		t:          t,
+		DB:         &db,
+		Registry:   rm,
+		Helper:     registration.NewRegistrationHelper(svgBaseDir, rm, store),
+		Errors:     store,
+		SVGBaseDir: svgBaseDir,
+	}
+}
+
+// Register registers the entity and fails the test if any of its definitions could not be registered.
+func (h *Harness) Register(en registration.RegisterableEntity) {
+	h.t.Helper()
+
+	h.Helper.Register(en)
+	require.Empty(h.t, h.Errors.EntityErrors)
+	require.Empty(h.t, h.Errors.InvalidDefinitions)
+}
+
+// AttachPolicy stores a policy of the registered model.
+// Policies are not part of PackagingUnits, so they are attached to the model directly.
+func (h *Harness) AttachPolicy(modelName string, policy corev1beta1.PolicyDefinition) {
+	h.t.Helper()
+
+	var m model.ModelDefinition
+	require.NoError(h.t, h.DB.Where("name = ?", modelName).First(&m).Error)
+	policy.ID = uuid.Must(uuid.NewV4())
+	policy.ModelID = m.ID
+	require.NoError(h.t, h.DB.Omit(clause.Associations).Create(&policy).Error)
+}
+
+// Snapshot holds the entities of a model read back from the registry, comparable across registries.
+type Snapshot struct {
+	Models        []SnapshotModel        `json:"models"`
+	Components    []SnapshotComponent    `json:"components"`
+	Relationships []SnapshotRelationship `json:"relationships"`
+	Connections   []SnapshotConnection   `json:"connections"`
+	Categories    []string               `json:"categories"`
+	Policies      []SnapshotPolicy       `json:"policies"`
+}
+
+type SnapshotModel struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	DisplayName string `json:"displayName"`
+	Category    string `json:"category"`
+	Registrant  string `json:"registrant"`
+}
+
+type SnapshotComponent struct {
+	Kind         string `json:"kind"`
+	APIVersion   string `json:"apiVersion"`
+	DisplayName  string `json:"displayName"`
+	Model        string `json:"model"`
+	ModelVersion string `json:"modelVersion"`
+	Category     string `json:"category"`
+	Registrant   string `json:"registrant"`
+}
+
+type SnapshotRelationship struct {
+	Key          string `json:"key"`
+	Model        string `json:"model"`
+	ModelVersion string `json:"modelVersion"`
+}
+
+type SnapshotConnection struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Type    string `json:"type"`
+	SubType string `json:"subType"`
+	Model   string `json:"model"`
+}
+
+type SnapshotPolicy struct {
+	Kind    string `json:"kind"`
+	SubType string `json:"subType"`
+}
+
+// Snapshot reads the entities of the model, of every entity.EntityType, through the registry filters.
+// Generated IDs, timestamps and SVG paths are left out so that snapshots are comparable across registries.
+func (h *Harness) Snapshot(modelName string) Snapshot {
+	h.t.Helper()
+
+	s := Snapshot{}
+	get := func(f entity.Filter) []entity.Entity {
+		entities, _, _, err := h.Registry.GetEntities(f)
+		require.NoError(h.t, err, "%T", f)
+		return entities
+	}
+
+	for _, e := range get(&regv1beta1.ModelFilter{Name: modelName}) {
+		m := e.(*model.ModelDefinition)
+		s.Models = append(s.Models, SnapshotModel{
+			Name:        m.Name,
+			Version:     m.Model.Version,
+			DisplayName: m.DisplayName,
+			Category:    m.Category.Name,
+			Registrant:  m.Registrant.Kind,
+		})
+		for _, c := range get(&regv1beta1.CategoryFilter{Name: m.Category.Name}) {
+			s.Categories = append(s.Categories, c.(*category.CategoryDefinition).Name)
+		}
+	}
+	for _, e := range get(&regv1beta1.ComponentFilter{ModelName: modelName}) {
+		c := e.(*component.ComponentDefinition)
+		sc := SnapshotComponent{Kind: c.Component.Kind, APIVersion: c.Component.Version, DisplayName: c.DisplayName}
+		if c.Model != nil {
+			sc.Model, sc.ModelVersion = c.Model.Name, c.Model.Model.Version
+			sc.Category, sc.Registrant = c.Model.Category.Name, c.Model.Registrant.Kind
+		}
+		s.Components = append(s.Components, sc)
+	}
+	for _, e := range get(&v1alpha3.RelationshipFilter{ModelName: modelName}) {
+		r := e.(*relationship.RelationshipDefinition)
+		s.Relationships = append(s.Relationships, SnapshotRelationship{
+			Key:          fmt.Sprintf("%s/%s/%s", r.Kind, r.RelationshipType, r.SubType),
+			Model:        r.Model.Name,
+			ModelVersion: r.Model.Model.Version,
+		})
+	}
+	for _, e := range get(&regv1beta1.ConnectionFilter{ModelName: modelName}) {
+		c := e.(*connectionv1beta3.ConnectionDefinition)
+		sc := SnapshotConnection{Name: c.Name, Kind: c.Kind, Type: c.ConnectionType, SubType: c.SubType}
+		if c.ModelReference != nil {
+			sc.Model = c.ModelReference.Name
+		}
+		s.Connections = append(s.Connections, sc)
+	}
+	for _, e := range get(&regv1beta1.PolicyFilter{ModelName: modelName}) {
+		p := e.(*corev1beta1.PolicyDefinition)
+		s.Policies = append(s.Policies, SnapshotPolicy{Kind: p.Kind, SubType: p.SubType})
+	}
+
+	sort.Slice(s.Components, func(i, j int) bool {
+		if s.Components[i].Kind != s.Components[j].Kind {
+			return s.Components[i].Kind < s.Components[j].Kind
+		}
+		return s.Components[i].APIVersion < s.Components[j].APIVersion
+	})
+	sort.Slice(s.Relationships, func(i, j int) bool { return s.Relationships[i].Key < s.Relationships[j].Key })
+	sort.Slice(s.Connections, func(i, j int) bool { return s.Connections[i].Name < s.Connections[j].Name })
+	sort.Strings(s.Categories)
+	return s
+}
+
+// AssertGolden compares the JSON encoding of v with the golden file, or rewrites the golden file when update is true,
+// e.g. when the test is run with an -update flag after an intended change.
+func AssertGolden(t testing.TB, golden string, v interface{}, update bool) {
+	t.Helper()
+
+	byt, err := json.MarshalIndent(v, "", "  ")
+	require.NoError(t, err)
+	if update {
+		require.NoError(t, os.WriteFile(golden, append(byt, '\n'), 0644))
+		return
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(byt))
+}
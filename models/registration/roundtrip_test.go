@@ -0,0 +1,76 @@
+package registration_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1beta1 "github.com/meshery/meshkit/models/meshmodel/core/v1beta1"
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	"github.com/meshery/meshkit/models/registration"
+	"github.com/meshery/meshkit/models/registration/registrationtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run `go test ./models/registration -run RoundTrip -update` to regenerate the golden files after an intended change.
+var updateGolden = flag.Bool("update", false, "update the golden files of the registry round-trip tests")
+
+const roundTripModel = "roundtrip-model"
+
+var roundTripFixture = filepath.Join("testdata", "roundtrip", "fixture")
+
+func registerRoundTripFixture(t *testing.T) *registrationtest.Harness {
+	t.Helper()
+
+	h := registrationtest.NewHarness(t)
+	h.Register(registration.NewDir(roundTripFixture))
+	h.AttachPolicy(roundTripModel, corev1beta1.PolicyDefinition{Kind: "network-policy", Version: "v1.0.0", SubType: "validation"})
+	return h
+}
+
+func TestRegistryRoundTrip(t *testing.T) {
+	h := registerRoundTripFixture(t)
+
+	snapshot := h.Snapshot(roundTripModel)
+	registrationtest.AssertGolden(t, filepath.Join("testdata", "roundtrip", "fixture.golden.json"), snapshot, *updateGolden)
+
+	// Every entity type of the registry must be covered by the fixture.
+	covered := map[entity.EntityType]int{
+		entity.Model:                  len(snapshot.Models),
+		entity.ComponentDefinition:    len(snapshot.Components),
+		entity.RelationshipDefinition: len(snapshot.Relationships),
+		entity.ConnectionDefinition:   len(snapshot.Connections),
+		entity.Category:               len(snapshot.Categories),
+		entity.PolicyDefinition:       len(snapshot.Policies),
+	}
+	for entityType, count := range covered {
+		assert.NotZero(t, count, "no %s read back from the registry", entityType)
+	}
+}
+
+func TestRegistryExportRoundTrip(t *testing.T) {
+	for _, format := range []registration.ExportFormat{registration.ExportFormatDir, registration.ExportFormatTarGz, registration.ExportFormatOCI} {
+		t.Run(string(format), func(t *testing.T) {
+			source := registerRoundTripFixture(t)
+			expected := source.Snapshot(roundTripModel)
+			// Policies are not part of the exported PackagingUnit.
+			expected.Policies = nil
+
+			dest := filepath.Join(t.TempDir(), "export")
+			require.NoError(t, source.Helper.ExportModel(roundTripModel, "v1.0.0", format, dest))
+
+			target := registrationtest.NewHarness(t)
+			target.Register(registration.NewDir(dest))
+			assert.Equal(t, expected, target.Snapshot(roundTripModel))
+
+			// SVGs are embedded into the exported definitions and written to the svgBaseDir of the target.
+			m := target.Helper.PkgUnits[0].Model
+			require.NotNil(t, m.Metadata)
+			svg, err := os.ReadFile(filepath.Join(target.SVGBaseDir, roundTripModel, "color", roundTripModel+"-color.svg"))
+			require.NoError(t, err)
+			assert.Contains(t, string(svg), "<circle")
+		})
+	}
+}
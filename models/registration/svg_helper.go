@@ -1,6 +1,7 @@
 package registration
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,16 @@ import (
 var UISVGPaths = make([]string, 1)
 
 func WriteAndReplaceSVGWithFileSystemPath(svgColor, svgWhite, svgComplete string, baseDir, dirname, filename string, isModel bool) (svgColorPath, svgWhitePath, svgCompletePath string) {
+	svgColorPath, svgWhitePath, svgCompletePath, err := writeSVGs(svgColor, svgWhite, svgComplete, baseDir, dirname, filename)
+	if err != nil {
+		fmt.Println(err)
+	}
+	return
+}
+
+// writeSVGs writes the non-empty SVGs under baseDir/dirname and returns the paths replacing them.
+// It stops at the first failure, returning the paths of the SVGs written so far.
+func writeSVGs(svgColor, svgWhite, svgComplete string, baseDir, dirname, filename string) (svgColorPath, svgWhitePath, svgCompletePath string, err error) {
 	filename = strings.ToLower(filename)
 	successCreatingDirectory := false
 	defer func() {
@@ -17,73 +28,75 @@ func WriteAndReplaceSVGWithFileSystemPath(svgColor, svgWhite, svgComplete string
 			UISVGPaths = append(UISVGPaths, filepath.Join(baseDir, dirname))
 		}
 	}()
-	if svgColor != "" {
-		path := filepath.Join(baseDir, dirname, "color")
-		err := os.MkdirAll(path, 0777)
-		if err != nil {
-			fmt.Println(err)
-			return
+	write := func(svg, variant string) (string, error) {
+		path := filepath.Join(baseDir, dirname, variant)
+		if err := os.MkdirAll(path, 0777); err != nil {
+			return "", err
 		}
 		successCreatingDirectory = true
 
-		f, err := os.Create(filepath.Join(path, filename+"-color.svg"))
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		_, err = f.WriteString(svgColor)
-		if err != nil {
-			fmt.Println(err)
-			return
+		name := filename + "-" + variant + ".svg"
+		if err := os.WriteFile(filepath.Join(path, name), []byte(svg), 0666); err != nil {
+			return "", err
 		}
-		svgColorPath = getRelativePathForAPI(baseDir, filepath.Join(dirname, "color", filename+"-color.svg")) //Replace the actual SVG with path to SVG
-
+		return getRelativePathForAPI(baseDir, filepath.Join(dirname, variant, name)), nil //Replace the actual SVG with path to SVG
 	}
-	if svgWhite != "" {
-		path := filepath.Join(baseDir, dirname, "white")
-		err := os.MkdirAll(path, 0777)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		successCreatingDirectory = true
 
-		f, err := os.Create(filepath.Join(path, filename+"-white.svg"))
-		if err != nil {
-			fmt.Println(err)
+	if svgColor != "" {
+		if svgColorPath, err = write(svgColor, "color"); err != nil {
 			return
 		}
-		_, err = f.WriteString(svgWhite)
-		if err != nil {
-			fmt.Println(err)
+	}
+	if svgWhite != "" {
+		if svgWhitePath, err = write(svgWhite, "white"); err != nil {
 			return
 		}
-		svgWhitePath = getRelativePathForAPI(baseDir, filepath.Join(dirname, "white", filename+"-white.svg")) //Replace the actual SVG with path to SVG
-
 	}
 	if svgComplete != "" {
-		path := filepath.Join(baseDir, dirname, "complete")
-		err := os.MkdirAll(path, 0777)
-		if err != nil {
-			fmt.Println(err)
+		if svgCompletePath, err = write(svgComplete, "complete"); err != nil {
 			return
 		}
-		successCreatingDirectory = true
+	}
+	return
+}
 
-		f, err := os.Create(filepath.Join(path, filename+"-complete.svg"))
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		_, err = f.WriteString(svgComplete)
-		if err != nil {
-			fmt.Println(err)
-			return
+// svgWriter writes the SVGs of the registered entities to the baseDir. When deferred, the SVGs are only written once
+// flush is called, e.g. once the registration of their entities is committed.
+type svgWriter struct {
+	baseDir  string
+	deferred bool
+	pending  []func() error
+}
+
+// write writes the SVGs and returns the paths replacing them, see WriteAndReplaceSVGWithFileSystemPath.
+func (w *svgWriter) write(svgColor, svgWhite, svgComplete string, dirname, filename string, isModel bool) (svgColorPath, svgWhitePath, svgCompletePath string) {
+	if !w.deferred {
+		return WriteAndReplaceSVGWithFileSystemPath(svgColor, svgWhite, svgComplete, w.baseDir, dirname, filename, isModel)
+	}
+	w.pending = append(w.pending, func() error {
+		_, _, _, err := writeSVGs(svgColor, svgWhite, svgComplete, w.baseDir, dirname, filename)
+		return err
+	})
+	filename = strings.ToLower(filename)
+	path := func(svg, variant string) string {
+		if svg == "" {
+			return ""
 		}
-		svgCompletePath = getRelativePathForAPI(baseDir, filepath.Join(dirname, "complete", filename+"-complete.svg")) //Replace the actual SVG with path to SVG
+		return getRelativePathForAPI(w.baseDir, filepath.Join(dirname, variant, filename+"-"+variant+".svg"))
+	}
+	return path(svgColor, "color"), path(svgWhite, "white"), path(svgComplete, "complete")
+}
 
+// flush writes the deferred SVGs, returning the failures to write them. The entities already point at their paths.
+func (w *svgWriter) flush() error {
+	var errs []error
+	for _, write := range w.pending {
+		if err := write(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return
+	w.pending = nil
+	return errors.Join(errs...)
 }
 
 func getRelativePathForAPI(baseDir, path string) string {
@@ -1,10 +1,155 @@
 package registration
 
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	"github.com/meshery/meshkit/models/oci"
+	"github.com/meshery/meshkit/utils"
+)
+
 type Tar struct {
-	_ string
+	name   string
+	reader io.Reader
+}
+
+/*
+NewTar accepts a stream of a tar or a gzip compressed tar (tar.gz) archive.
+`name` identifies the archive (usually the filename) and is only used while reporting errors.
+The archive should contain one and only one `model`, just like the directory consumed by `NewDir`.
+*/
+func NewTar(name string, r io.Reader) Tar {
+	return Tar{name: name, reader: r}
 }
 
-func (t Tar) PkgUnit(regErrStore RegistrationErrorStore) (PackagingUnit, error) {
+/*
+PkgUnit reads the archive in memory and finds out if the entries inside it are any valid meshery definitions. Valid meshery definitions are added to the PackagingUnit struct.
+Nested tar and tar.gz archives as well as OCI artifacts are processed recursively.
+Invalid definitions are stored in the regErrStore with error data.
+*/
+func (t Tar) PkgUnit(regErrStore RegistrationErrorStore) (_ PackagingUnit, err error) {
 	pkg := PackagingUnit{}
+
+	if t.reader == nil {
+		err = ErrTarPkgUnitParseFail(t.name, fmt.Errorf("no archive stream provided"))
+		regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), t.name, err)
+		return pkg, err
+	}
+
+	err = processTar(t.name, t.reader, &pkg, regErrStore)
+	if err != nil {
+		err = ErrTarPkgUnitParseFail(t.name, err)
+		regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), t.name, err)
+		return pkg, err
+	}
+
+	if err = ensureModelPresent(pkg, t.name, regErrStore); err != nil {
+		return pkg, err
+	}
+
 	return pkg, nil
 }
+
+// processTar walks every entry of the (optionally gzip compressed) tar stream.
+// Only a malformed archive is reported as an error, invalid entries are recorded in the regErrStore.
+func processTar(archivePath string, r io.Reader, pkg *PackagingUnit, regErrStore RegistrationErrorStore) error {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, err := br.Peek(2); err == nil && isGzip(magic) {
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = gzr.Close() }()
+		src = gzr
+	}
+
+	tr := tar.NewReader(src)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		path := filepath.Join(archivePath, filepath.FromSlash(header.Name))
+		if isNonEntityFile(path) {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+
+		// Check if the entry is an OCI artifact
+		if oci.IsOCIArtifact(data) {
+			img, err := imageFromArchive(data)
+			if err == nil {
+				err = processImage(path, img, pkg, regErrStore)
+			}
+			if err != nil {
+				err = ErrOCIImagePkgUnitParseFail(path, err)
+				regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), filepath.Base(path), err)
+				regErrStore.AddInvalidDefinition(path, err)
+			}
+			continue
+		}
+
+		if isGzip(data) || isTar(data) {
+			if err := processTar(path, bytes.NewReader(data), pkg, regErrStore); err != nil {
+				err = ErrTarPkgUnitParseFail(path, err)
+				regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), filepath.Base(path), err)
+				regErrStore.AddInvalidDefinition(path, err)
+			}
+			continue
+		}
+
+		if err := processEntityData(path, data, isYamlName(path), pkg, regErrStore); err != nil {
+			return err
+		}
+	}
+}
+
+// processEntityData converts YAML content to JSON before handing it over to processEntity.
+// Archive entries only exist in memory, so the caller tells whether the data is YAML from its name or media type.
+func processEntityData(path string, data []byte, isYaml bool, pkg *PackagingUnit, regErrStore RegistrationErrorStore) error {
+	content := data
+	if isYaml {
+		var err error
+		content, err = utils.YAMLToJSON(content)
+		if err != nil {
+			regErrStore.InsertEntityRegError("", "", entity.EntityType("unknown"), filepath.Base(path), err)
+			return nil
+		}
+	}
+	return processEntity(path, content, pkg, regErrStore)
+}
+
+// isYamlName reports whether an archive entry is a YAML document based on its extension.
+func isYamlName(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func isGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// isTar checks for the POSIX/GNU "ustar" magic present in the header block of tar archives.
+func isTar(data []byte) bool {
+	const magicOffset = 257
+	return len(data) >= magicOffset+5 && string(data[magicOffset:magicOffset+5]) == "ustar"
+}
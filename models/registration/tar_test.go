@@ -0,0 +1,175 @@
+package registration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/meshery/meshkit/models/meshmodel/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRegErrStore struct {
+	invalidDefinitions map[string]error
+	entityErrors       []error
+}
+
+func newTestRegErrStore() *testRegErrStore {
+	return &testRegErrStore{invalidDefinitions: map[string]error{}}
+}
+
+func (s *testRegErrStore) AddInvalidDefinition(path string, err error) {
+	s.invalidDefinitions[path] = err
+}
+
+func (s *testRegErrStore) InsertEntityRegError(_ string, _ string, _ entity.EntityType, _ string, err error) {
+	s.entityErrors = append(s.entityErrors, err)
+}
+
+const testModelDocument = `{
+	"schemaVersion": "models.meshery.io/v1beta1",
+	"name": "test-model",
+	"version": "v1.0.0",
+	"displayName": "Test Model",
+	"model": {
+		"version": "v1.0.0"
+	},
+	"registrant": {
+		"kind": "github"
+	}
+}`
+
+const testRelationshipDocument = `{
+	"schemaVersion": "relationships.meshery.io/v1beta2",
+	"kind": "edge",
+	"type": "binding",
+	"subType": "firewall",
+	"model": {
+		"name": "test-model",
+		"model": {
+			"version": "v1.0.0"
+		}
+	},
+	"version": "v1.0.0"
+}`
+
+func buildTestTar(t *testing.T, files map[string]string, compress bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	if !compress {
+		return buf.Bytes()
+	}
+
+	var gzBuf bytes.Buffer
+	gzw := gzip.NewWriter(&gzBuf)
+	_, err := gzw.Write(buf.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gzw.Close())
+	return gzBuf.Bytes()
+}
+
+func TestTarPkgUnit(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"test-model/model.json":                       testModelDocument,
+		"test-model/relationships/binding.json":       testRelationshipDocument,
+		"test-model/components/icon.svg":              "<svg></svg>",
+		"test-model/components/not-a-definition.json": `{"kind": "Pod"}`,
+	}
+
+	for _, compress := range []bool{false, true} {
+		regErrStore := newTestRegErrStore()
+		pkg, err := NewTar("test-model.tar", bytes.NewReader(buildTestTar(t, files, compress))).PkgUnit(regErrStore)
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-model", pkg.Model.Name)
+		assert.Len(t, pkg.Relationships, 1)
+		assert.Len(t, regErrStore.invalidDefinitions, 1)
+	}
+}
+
+const testRelationshipYAML = `schemaVersion: relationships.meshery.io/v1beta2
+kind: edge
+type: binding
+subType: firewall
+model:
+  name: test-model
+  model:
+    version: v1.0.0
+version: v1.0.0
+`
+
+func TestTarPkgUnitYamlEntries(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"test-model/model.json":                     testModelDocument,
+		"test-model/relationships/binding.yaml":     testRelationshipYAML,
+		"test-model/relationships/not-yaml.json":    testRelationshipYAML,
+		"test-model/relationships/binding-copy.yml": testRelationshipYAML,
+	}
+
+	regErrStore := newTestRegErrStore()
+	pkg, err := NewTar("test-model.tar", bytes.NewReader(buildTestTar(t, files, false))).PkgUnit(regErrStore)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-model", pkg.Model.Name)
+	assert.Len(t, pkg.Relationships, 2)
+	assert.Len(t, regErrStore.invalidDefinitions, 1)
+	assert.Contains(t, regErrStore.invalidDefinitions, "test-model.tar/test-model/relationships/not-yaml.json")
+}
+
+func TestTarPkgUnitNestedArchive(t *testing.T) {
+	t.Parallel()
+
+	nested := buildTestTar(t, map[string]string{
+		"relationships/binding.json": testRelationshipDocument,
+	}, true)
+	archive := buildTestTar(t, map[string]string{
+		"model.json":           testModelDocument,
+		"relationships.tar.gz": string(nested),
+	}, true)
+
+	pkg, err := NewTar("test-model.tar.gz", bytes.NewReader(archive)).PkgUnit(newTestRegErrStore())
+	require.NoError(t, err)
+	assert.Len(t, pkg.Relationships, 1)
+}
+
+func TestTarPkgUnitWithoutModel(t *testing.T) {
+	t.Parallel()
+
+	archive := buildTestTar(t, map[string]string{
+		"relationships/binding.json": testRelationshipDocument,
+	}, false)
+
+	regErrStore := newTestRegErrStore()
+	_, err := NewTar("no-model.tar", bytes.NewReader(archive)).PkgUnit(regErrStore)
+	require.Error(t, err)
+	assert.NotEmpty(t, regErrStore.entityErrors)
+}
+
+func TestTarPkgUnitMalformedArchive(t *testing.T) {
+	t.Parallel()
+
+	regErrStore := newTestRegErrStore()
+	_, err := NewTar("broken.tar.gz", bytes.NewReader([]byte{0x1f, 0x8b, 0x00})).PkgUnit(regErrStore)
+	require.Error(t, err)
+	assert.NotEmpty(t, regErrStore.entityErrors)
+}